@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"notion-tools/internal/notion"
+)
+
+// checker prints the outcome of each preflight check and counts the failures
+type checker struct {
+	failed int
+}
+
+func (c *checker) report(what string, err error) bool {
+	if err != nil {
+		c.failed++
+		fmt.Printf("FAIL  %s: %v\n", what, err)
+		return false
+	}
+	fmt.Printf("ok    %s\n", what)
+	return true
+}
+
+// runCheck verifies the token, both data sources and the configured
+// properties with read-only calls, so configuration mistakes surface before
+// a run starts rather than midway
+func runCheck(ctx context.Context, client *notion.Client, cfg config) error {
+	var c checker
+
+	me, err := client.GetMe(ctx)
+	if err == nil {
+		c.report(fmt.Sprintf("token belongs to %q", me.Name), nil)
+	} else {
+		c.report("token", err)
+	}
+
+	schema, err := client.GetDataSource(ctx, cfg.dataSource)
+	what := "data source " + cfg.dataSource + " is readable"
+	if err == nil {
+		what = "data source " + schema.Info().String() + " is readable"
+	}
+	if c.report(what, err) {
+		for _, field := range cfg.fields {
+			c.report(fmt.Sprintf("source field %q exists", field), schema.CheckProperty(field))
+		}
+		if cfg.titleField != "" {
+			c.report(fmt.Sprintf("title field %q is the title property", cfg.titleField), schema.CheckProperty(cfg.titleField, "title"))
+		} else if name, ok := schema.TitleProperty(); ok {
+			c.report(fmt.Sprintf("title property %q detected", name), nil)
+		} else {
+			c.report("title property detected", errors.New("none found, set -title-field"))
+		}
+
+		err := schema.CheckProperty(cfg.relationField, "relation")
+		if c.report(fmt.Sprintf("relation field %q is a relation", cfg.relationField), err) {
+			rel := schema.Properties[cfg.relationField].Relation
+			if rel != nil && rel.DataSourceID != "" && rel.DataSourceID != cfg.peopleDB {
+				err = fmt.Errorf("it points to data source %s", rel.DataSourceID)
+			}
+			c.report(fmt.Sprintf("relation field %q points to the people data source", cfg.relationField), err)
+		}
+	}
+
+	people, err := client.GetDataSource(ctx, cfg.peopleDB)
+	what = "people data source " + cfg.peopleDB + " is readable"
+	if err == nil {
+		what = "people data source " + people.Info().String() + " is readable"
+	}
+	if c.report(what, err) {
+		title, ok := cfg.peopleTitle, true
+		if title != "" {
+			c.report(fmt.Sprintf("people title field %q is the title property", title), people.CheckProperty(title, "title"))
+		} else if title, ok = people.TitleProperty(); ok {
+			c.report(fmt.Sprintf("people title property %q detected", title), nil)
+		} else {
+			c.report("people title property detected", errors.New("none found, set -people-title-field"))
+		}
+		for _, p := range cfg.defaultProps {
+			err := people.CheckProperty(p.name, p.typ)
+			if ok && p.name == title {
+				err = errors.New("it is the title property")
+			}
+			c.report(fmt.Sprintf("default property %q is a %s", p.name, p.typ), err)
+		}
+	}
+
+	if c.failed > 0 {
+		return fmt.Errorf("preflight check: %d checks failed", c.failed)
+	}
+	return nil
+}
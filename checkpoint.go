@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// checkpoint records how far a sync got so an interrupted run can resume.
+// Cursor is where the current batch of pages started and Done lists the pages
+// of that batch already synced; earlier batches are complete.
+type checkpoint struct {
+	DataSource string   `json:"data_source"`
+	Cursor     string   `json:"cursor,omitempty"`
+	Done       []string `json:"done_pages,omitempty"`
+
+	path string
+	mu   sync.Mutex
+	done map[string]bool
+}
+
+// loadCheckpoint reads the checkpoint at path, or starts a new one when the
+// file does not exist. A checkpoint of another data source is an error.
+func loadCheckpoint(path, dataSource string) (*checkpoint, error) {
+	cp := &checkpoint{DataSource: dataSource, path: path, done: map[string]bool{}}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, fmt.Errorf("parse checkpoint %s: %w", path, err)
+	}
+	if cp.DataSource != dataSource {
+		return nil, fmt.Errorf("checkpoint %s belongs to data source %s, not %s; remove it to start over", path, cp.DataSource, dataSource)
+	}
+	for _, id := range cp.Done {
+		cp.done[id] = true
+	}
+	return cp, nil
+}
+
+// startCursor returns the cursor to resume from, or nil to start at the beginning
+func (cp *checkpoint) startCursor() *string {
+	if cp.Cursor == "" {
+		return nil
+	}
+	c := cp.Cursor
+	return &c
+}
+
+// isDone reports whether the page was synced by an earlier run
+func (cp *checkpoint) isDone(pageID string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.done[pageID]
+}
+
+// markDone records a synced page of the current batch
+func (cp *checkpoint) markDone(pageID string) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if !cp.done[pageID] {
+		cp.done[pageID] = true
+		cp.Done = append(cp.Done, pageID)
+	}
+}
+
+// advance moves past a completed batch to the one starting at next and saves
+func (cp *checkpoint) advance(next *string) error {
+	cp.mu.Lock()
+	cp.Cursor = ""
+	if next != nil {
+		cp.Cursor = *next
+	}
+	cp.Done = nil
+	cp.done = map[string]bool{}
+	cp.mu.Unlock()
+	return cp.save()
+}
+
+// save writes the checkpoint to its file
+func (cp *checkpoint) save() error {
+	cp.mu.Lock()
+	b, err := json.MarshalIndent(cp, "", "  ")
+	cp.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := writeFileAtomic(cp.path, append(b, '\n')); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// clear removes the checkpoint after a run completed
+func (cp *checkpoint) clear() error {
+	if err := os.Remove(cp.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSyncResumesFromCheckpoint(t *testing.T) {
+	srv := newFakeNotion(t, nil)
+	srv.batch = 2
+	srv.sources = []fakeSource{{"p1", "Alice"}, {"p2", "Bob"}, {"p3", "Carol"}, {"p4", "Dave"}, {"p5", "Erin"}}
+	srv.failUpdate = map[string]bool{"p4": true}
+
+	cfg := syncConfig()
+	cfg.checkpoint = filepath.Join(t.TempDir(), "sync.checkpoint")
+
+	// The first batch completes, the second fails at p4 after p3 was synced.
+	if _, err := NewSyncer(srv.client(), cfg).Run(t.Context()); err == nil {
+		t.Fatal("first run succeeded, want p4's failure")
+	}
+	b, err := os.ReadFile(cfg.checkpoint)
+	if err != nil {
+		t.Fatalf("no checkpoint after the failed run: %v", err)
+	}
+	var saved struct {
+		DataSource string   `json:"data_source"`
+		Cursor     string   `json:"cursor"`
+		Done       []string `json:"done_pages"`
+	}
+	if err := json.Unmarshal(b, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.DataSource != "src" || saved.Cursor != "2" || !slices.Equal(saved.Done, []string{"p3"}) {
+		t.Errorf("checkpoint = %+v, want data source src, cursor 2 and p3 done", saved)
+	}
+
+	srv.mu.Lock()
+	srv.failUpdate = nil
+	srv.queries = nil
+	srv.mu.Unlock()
+	rep, err := NewSyncer(srv.client(), cfg).Run(t.Context())
+	if err != nil {
+		t.Fatalf("resumed run: %v", err)
+	}
+
+	if c := srv.queries[0].StartCursor; c == nil || *c != "2" {
+		t.Errorf("resumed query started at %v, want cursor 2", c)
+	}
+	var synced []string
+	for _, p := range rep.Pages {
+		synced = append(synced, p.ID)
+	}
+	if !slices.Equal(synced, []string{"p4", "p5"}) {
+		t.Errorf("resumed run synced %q, want p4 and p5", synced)
+	}
+	// p4 failed once and succeeded once; every other page was updated exactly once.
+	want := map[string]int{"p1": 1, "p2": 1, "p3": 1, "p4": 2, "p5": 1}
+	for id, n := range want {
+		if srv.updates[id] != n {
+			t.Errorf("page %s updated %d times, want %d", id, srv.updates[id], n)
+		}
+	}
+	if _, err := os.Stat(cfg.checkpoint); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("checkpoint left behind after the run completed: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"notion-tools/internal/notion"
+)
+
+// defaultProp is a property set on every people page the sync creates
+type defaultProp struct {
+	name  string
+	typ   string
+	raw   string
+	value notion.PropertyValue
+}
+
+// defaultPropList collects repeated -default-prop flags of the form
+// "Name=value:type", the type being one the -types mapping accepts
+type defaultPropList []defaultProp
+
+func (l *defaultPropList) String() string {
+	parts := make([]string, len(*l))
+	for i, p := range *l {
+		parts[i] = p.name + "=" + p.raw + ":" + p.typ
+	}
+	return strings.Join(parts, " ")
+}
+
+func (l *defaultPropList) Set(v string) error {
+	name, rest, ok := strings.Cut(v, "=")
+	i := strings.LastIndex(rest, ":")
+	if !ok || i < 0 {
+		return fmt.Errorf("invalid default property %q: expected Name=value:type", v)
+	}
+	name, raw, typ := strings.TrimSpace(name), strings.TrimSpace(rest[:i]), strings.TrimSpace(rest[i+1:])
+	switch {
+	case name == "":
+		return fmt.Errorf("invalid default property %q: empty name", v)
+	case raw == "":
+		return fmt.Errorf("invalid default property %q: empty value", v)
+	}
+	for _, p := range *l {
+		if p.name == name {
+			return fmt.Errorf("duplicate default property %q", name)
+		}
+	}
+	value, err := buildPropertyValue(typ, raw)
+	if err != nil {
+		return fmt.Errorf("default property %q: %w", name, err)
+	}
+	*l = append(*l, defaultProp{name: name, typ: typ, raw: raw, value: value})
+	return nil
+}
+
+// properties returns the values to merge into created pages, nil when there are none
+func (l defaultPropList) properties() map[string]notion.PropertyValue {
+	if len(l) == 0 {
+		return nil
+	}
+	props := make(map[string]notion.PropertyValue, len(l))
+	for _, p := range l {
+		props[p.name] = p.value
+	}
+	return props
+}
+
+// checkDefaultProps checks that the people data source has each default
+// property with the type it is given, and that none is its title property
+func checkDefaultProps(schema *notion.Schema, title string, props defaultPropList) error {
+	for _, p := range props {
+		if p.name == title {
+			return fmt.Errorf("default properties cannot set the title %q", title)
+		}
+		if err := schema.CheckProperty(p.name, p.typ); err != nil {
+			return fmt.Errorf("%w; fix -default-prop %s", err, p.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// RelationChange records how a page's relation changed, or would change in a dry run
+type RelationChange struct {
+	PageID  string   `json:"page_id"`
+	Title   string   `json:"title"`
+	Before  []string `json:"before"`
+	After   []string `json:"after"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	// New holds the names of people pages a dry run would create; they have no ID yet.
+	New []string `json:"new,omitempty"`
+}
+
+// newRelationChange compares the relation IDs before and after an update
+func newRelationChange(pageID, title string, before, after []string) RelationChange {
+	c := RelationChange{
+		PageID:  pageID,
+		Title:   title,
+		Before:  nonNil(before),
+		After:   nonNil(after),
+		Added:   []string{},
+		Removed: []string{},
+	}
+	for _, id := range after {
+		if !slices.Contains(before, id) {
+			c.Added = append(c.Added, id)
+		}
+	}
+	for _, id := range before {
+		if !slices.Contains(after, id) {
+			c.Removed = append(c.Removed, id)
+		}
+	}
+	return c
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// writeDiffReport writes the relation changes of a run to path as text or JSON,
+// ordered by page ID so reports of the same run compare cleanly
+func writeDiffReport(path, format string, changes []RelationChange) error {
+	changes = slices.Clone(changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].PageID < changes[j].PageID })
+
+	var data []byte
+	switch format {
+	case outputJSON:
+		b, err := json.MarshalIndent(nonNilChanges(changes), "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode diff report: %w", err)
+		}
+		data = append(b, '\n')
+	default:
+		var b strings.Builder
+		for _, c := range changes {
+			fmt.Fprintf(&b, "%s %s\n", c.PageID, c.Title)
+			for _, id := range c.Added {
+				fmt.Fprintf(&b, "  + %s\n", id)
+			}
+			for _, name := range c.New {
+				fmt.Fprintf(&b, "  + (new) %s\n", name)
+			}
+			for _, id := range c.Removed {
+				fmt.Fprintf(&b, "  - %s\n", id)
+			}
+		}
+		data = []byte(b.String())
+	}
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("write diff report: %w", err)
+	}
+	return nil
+}
+
+func nonNilChanges(c []RelationChange) []RelationChange {
+	if c == nil {
+		return []RelationChange{}
+	}
+	return c
+}
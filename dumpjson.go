@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"notion-tools/internal/notion"
+)
+
+// runDumpJSON writes every page of the data source, exactly as returned by the
+// API, to <out>/<page id>.json. No filter_properties are sent so nothing is trimmed.
+func runDumpJSON(ctx context.Context, client *notion.Client, cfg config) error {
+	if err := os.MkdirAll(cfg.out, 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	var cursor *string
+	count := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("dump stopped after %d pages: %w", count, err)
+		}
+		req := notion.QueryRequest{
+			PageSize:    notion.DefaultPageSize,
+			StartCursor: cursor,
+		}
+
+		var resp notion.RawQueryResponse
+		if err := client.Do(ctx, http.MethodPost, client.QueryPath(cfg.dataSource), nil, req, &resp); err != nil {
+			return err
+		}
+
+		for _, raw := range resp.Results {
+			var ref struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(raw, &ref); err != nil || ref.ID == "" {
+				return fmt.Errorf("query result without page id: %s", notion.TruncateBody(string(raw)))
+			}
+			path := filepath.Join(cfg.out, ref.ID+".json")
+			if err := os.WriteFile(path, raw, 0o644); err != nil {
+				return fmt.Errorf("write %s: %w", path, err)
+			}
+			count++
+		}
+
+		if err := resp.CheckCursor(); err != nil {
+			return fmt.Errorf("query data source %s: %w", cfg.dataSource, err)
+		}
+		if !resp.More() {
+			break
+		}
+		cursor = resp.Cursor()
+	}
+
+	infof("Wrote %d pages to %s\n", count, cfg.out)
+	return nil
+}
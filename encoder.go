@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	outputCSV  = "csv"
+	outputJSON = "json"
+	outputText = "text"
+
+	// defaultMultiValueSep joins multi-valued properties inside a single CSV cell
+	defaultMultiValueSep = "; "
+)
+
+// Encoder writes exported records in a particular output format.
+// WriteHeader is called once before any record; records are keyed by column.
+type Encoder interface {
+	WriteHeader([]string) error
+	WriteRecord(map[string][]string) error
+	Close() error
+}
+
+// columnMeta describes how to interpret an exported column
+type columnMeta struct {
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+}
+
+// metadataEncoder is implemented by encoders that can describe their columns.
+// WriteMetadata is called before WriteHeader.
+type metadataEncoder interface {
+	WriteMetadata(map[string]columnMeta) error
+}
+
+// newEncoder returns the encoder for the named output format. CSV cells
+// join multiple values with sep.
+func newEncoder(format string, w io.Writer, sep string) (Encoder, error) {
+	switch format {
+	case outputCSV:
+		return &csvEncoder{w: csv.NewWriter(w), sep: sep}, nil
+	case outputJSON:
+		return &jsonEncoder{w: bufio.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// csvEncoder writes one row per record, joining multiple values with sep.
+// The csv.Writer quotes cells holding commas, quotes or newlines per RFC 4180,
+// separators included.
+type csvEncoder struct {
+	w       *csv.Writer
+	sep     string
+	columns []string
+}
+
+func (e *csvEncoder) WriteHeader(columns []string) error {
+	e.columns = columns
+	return e.w.Write(columns)
+}
+
+func (e *csvEncoder) WriteRecord(rec map[string][]string) error {
+	row := make([]string, len(e.columns))
+	for i, col := range e.columns {
+		row[i] = strings.Join(rec[col], e.sep)
+	}
+	return e.w.Write(row)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonEncoder writes a JSON array of objects whose keys follow the header order.
+// When column metadata is given the array is wrapped as {"columns": ..., "records": [...]}.
+type jsonEncoder struct {
+	w       *bufio.Writer
+	meta    map[string]columnMeta
+	columns []string
+	count   int
+}
+
+func (e *jsonEncoder) WriteMetadata(meta map[string]columnMeta) error {
+	e.meta = meta
+	return nil
+}
+
+func (e *jsonEncoder) WriteHeader(columns []string) error {
+	e.columns = columns
+	if e.meta == nil {
+		_, err := e.w.WriteString("[")
+		return err
+	}
+
+	meta, err := json.Marshal(e.meta)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.WriteString(`{"columns": ` + string(meta) + `, "records": [`)
+	return err
+}
+
+func (e *jsonEncoder) WriteRecord(rec map[string][]string) error {
+	if e.columns == nil {
+		return errors.New("json encoder: WriteRecord called before WriteHeader")
+	}
+
+	var b strings.Builder
+	if e.count > 0 {
+		b.WriteString(",")
+	}
+	b.WriteString("\n  {")
+	for i, col := range e.columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		k, _ := json.Marshal(col)
+		values := rec[col]
+		if values == nil {
+			values = []string{}
+		}
+		v, err := json.Marshal(values)
+		if err != nil {
+			return err
+		}
+		b.Write(k)
+		b.WriteString(": ")
+		b.Write(v)
+	}
+	b.WriteString("}")
+	e.count++
+
+	_, err := e.w.WriteString(b.String())
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	if e.columns == nil {
+		if err := e.WriteHeader([]string{}); err != nil {
+			return err
+		}
+	}
+	closing := "\n]\n"
+	if e.meta != nil {
+		closing = "\n]}\n"
+	}
+	if _, err := e.w.WriteString(closing); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
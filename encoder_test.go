@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCSVEncoderQuotesCells(t *testing.T) {
+	values := [][]string{
+		{"plain"},
+		{"a,b"},
+		{`say "hi"`},
+		{"line one\nline two"},
+		{"crlf\r\nend"},
+		{" leading and trailing "},
+		{""},
+		{"x; y", "z"},
+		{"a,b", `"c"`, "d\ne"},
+		{"uses | pipe", "and, comma"},
+	}
+	for _, sep := range []string{defaultMultiValueSep, ",", "\n", `"`, "|"} {
+		t.Run(strings.NewReplacer("\n", `\n`).Replace(sep), func(t *testing.T) {
+			var buf bytes.Buffer
+			enc, err := newEncoder(outputCSV, &buf, sep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			columns := []string{"ID", "Value, with comma"}
+			if err := enc.WriteHeader(columns); err != nil {
+				t.Fatal(err)
+			}
+			for i, v := range values {
+				if err := enc.WriteRecord(map[string][]string{"ID": {string(rune('a' + i))}, columns[1]: v}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := enc.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("output does not parse as CSV: %v\n%s", err, buf.String())
+			}
+			if len(rows) != len(values)+1 {
+				t.Fatalf("got %d rows, want %d", len(rows), len(values)+1)
+			}
+			if !slices.Equal(rows[0], columns) {
+				t.Errorf("header = %q, want %q", rows[0], columns)
+			}
+			for i, v := range values {
+				row := rows[i+1]
+				// csv.Reader turns \r\n inside quoted fields into \n
+				want := strings.ReplaceAll(strings.Join(v, sep), "\r\n", "\n")
+				if len(row) != 2 || row[1] != want {
+					t.Errorf("row %d = %q, want the cell %q", i+1, row, want)
+				}
+			}
+		})
+	}
+}
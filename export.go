@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"notion-tools/internal/notion"
+)
+
+const (
+	// exportIDColumn is the export column holding the page ID
+	exportIDColumn = "id"
+
+	// Synthetic columns holding the page's own timestamps, added with -timestamps
+	createdTimeColumn    = "_created_time"
+	lastEditedTimeColumn = "_last_edited_time"
+)
+
+// runExport writes the properties of every page in the data source through the selected encoder
+func runExport(ctx context.Context, client *notion.Client, cfg config) error {
+	enc, err := newEncoder(cfg.output, os.Stdout, cfg.multiSep)
+	if err != nil {
+		return err
+	}
+
+	qp := notion.FilterProperties(cfg.columns...)
+	opts := notion.ExtractOptions{Location: cfg.location}
+
+	var (
+		schema *notion.Schema
+		info   notion.SourceInfo
+	)
+	if cfg.numberFormat {
+		if schema, err = client.GetDataSource(ctx, cfg.dataSource); err != nil {
+			return err
+		}
+		info = schema.Info()
+	} else if info, err = client.GetSourceInfo(ctx, cfg.dataSource); err != nil {
+		return err
+	}
+	infof("Exporting data source %s\n", info)
+
+	columns := cfg.columns
+	headerWritten := false
+	writeHeader := func() error {
+		headerWritten = true
+		if me, ok := enc.(metadataEncoder); ok && schema != nil {
+			if err := me.WriteMetadata(columnMetadata(schema, columns)); err != nil {
+				return err
+			}
+		}
+		header := []string{exportIDColumn}
+		if cfg.timestamps {
+			header = append(header, createdTimeColumn, lastEditedTimeColumn)
+		}
+		return enc.WriteHeader(append(header, columns...))
+	}
+
+	req := notion.QueryRequest{Sorts: cfg.sorts}
+	if !cfg.since.IsZero() {
+		req.Filter = notion.LastEditedOnOrAfter(cfg.since)
+	}
+	err = client.QueryEach(ctx, cfg.dataSource, req, qp, func(pg notion.Page) error {
+		if !headerWritten {
+			if len(columns) == 0 {
+				columns = propertyNames(pg)
+			}
+			if err := writeHeader(); err != nil {
+				return err
+			}
+		}
+
+		rec := map[string][]string{exportIDColumn: {pg.ID}}
+		if cfg.timestamps {
+			rec[createdTimeColumn] = []string{opts.FormatTime(pg.CreatedTime)}
+			rec[lastEditedTimeColumn] = []string{opts.FormatTime(pg.LastEditedTime)}
+		}
+		for _, col := range columns {
+			// Page responses list only the first related pages of long relations.
+			p, err := client.CompleteProperty(ctx, pg.ID, pg.Properties[col])
+			if err != nil {
+				return err
+			}
+			if cfg.relationTitles && p.Type == "relation" {
+				titles, err := relationTitles(ctx, client, p)
+				if err != nil {
+					return err
+				}
+				rec[col] = titles
+				continue
+			}
+			rec[col] = exportValues(schema, col, p, opts)
+		}
+		return enc.WriteRecord(rec)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !headerWritten {
+		if err := writeHeader(); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+// propertyNames returns the page's property names in a stable order
+func propertyNames(pg notion.Page) []string {
+	names := make([]string, 0, len(pg.Properties))
+	for name := range pg.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// relationTitles renders a relation property as the titles of its target pages
+func relationTitles(ctx context.Context, client *notion.Client, p notion.PropertyValue) ([]string, error) {
+	ids := notion.ExtractStrings(p)
+	titles, err := client.ResolveTitles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, titles[id])
+	}
+	return out, nil
+}
+
+// exportValues renders a property for export with opts, formatting numbers
+// per their schema format when a schema is given
+func exportValues(schema *notion.Schema, name string, p notion.PropertyValue, opts notion.ExtractOptions) []string {
+	if schema != nil && p.Type == "number" && p.Number != nil {
+		if ps, ok := schema.Properties[name]; ok && ps.Number != nil {
+			return []string{notion.FormatNumber(*p.Number, ps.Number.Format)}
+		}
+	}
+	return notion.ExtractStringsWith(p, opts)
+}
+
+// columnMetadata describes the exported columns using the data source schema
+func columnMetadata(schema *notion.Schema, columns []string) map[string]columnMeta {
+	meta := make(map[string]columnMeta, len(columns))
+	for _, col := range columns {
+		ps, ok := schema.Properties[col]
+		if !ok {
+			continue
+		}
+		m := columnMeta{Type: ps.Type}
+		if ps.Number != nil {
+			m.Format = ps.Number.Format
+		}
+		meta[col] = m
+	}
+	return meta
+}
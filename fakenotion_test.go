@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"notion-tools/internal/notion"
+)
+
+// fakeNotion serves the source data source "src", whose pages name their
+// persons in Who and link them in People, and the people data source
+// "people". It records the relations written to each page.
+type fakeNotion struct {
+	*httptest.Server
+
+	// batch is how many source pages a query returns at most (default 100)
+	batch int
+	// failUpdate makes updates of these pages fail with a validation error
+	failUpdate map[string]bool
+
+	mu        sync.Mutex
+	sources   []fakeSource
+	people    []string // titles by creation; the page ID is "person-" + index
+	relations map[string][]string
+	updates   map[string]int
+	queries   []notion.QueryRequest // queries of src, in order
+}
+
+// fakeSource is a page of the source data source. Its relation is always
+// served empty, whatever was written to it.
+type fakeSource struct {
+	id, who string
+}
+
+func newFakeNotion(t *testing.T, existing []string) *fakeNotion {
+	t.Helper()
+	f := &fakeNotion{people: existing, relations: map[string][]string{}, updates: map[string]int{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/data_sources/src":
+			f.write(t, w, map[string]any{"object": "data_source", "id": "src", "properties": map[string]any{
+				"Name":   map[string]any{"id": "title", "name": "Name", "type": "title", "title": map[string]any{}},
+				"Who":    map[string]any{"id": "who", "name": "Who", "type": "rich_text", "rich_text": map[string]any{}},
+				"People": map[string]any{"id": "ppl", "name": "People", "type": "relation", "relation": map[string]any{"data_source_id": "people"}},
+			}})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/data_sources/src/query":
+			var req notion.QueryRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode source query: %v", err)
+			}
+			f.write(t, w, f.query(req))
+
+		case r.Method == http.MethodPost && r.URL.Path == "/data_sources/people/query":
+			var req notion.QueryRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Filter == nil || req.Filter.Title == nil {
+				t.Errorf("query is not a title query: %v", err)
+				return
+			}
+			name := req.Filter.Title.Equals
+			var results []any
+			if id, ok := f.find(name); ok {
+				results = append(results, f.page(id, name))
+			}
+			f.write(t, w, map[string]any{"results": results, "has_more": false})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/pages":
+			var req notion.CreatePageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode create: %v", err)
+			}
+			name := notion.ExtractString(req.Properties["Name"])
+			f.mu.Lock()
+			f.people = append(f.people, name)
+			id := fmt.Sprintf("person-%d", len(f.people)-1)
+			f.mu.Unlock()
+			f.write(t, w, f.page(id, name))
+
+		case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/pages/"):
+			id := strings.TrimPrefix(r.URL.Path, "/pages/")
+			var req notion.UpdatePageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode update: %v", err)
+			}
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.updates[id]++
+			if f.failUpdate[id] {
+				w.WriteHeader(http.StatusBadRequest)
+				f.write(t, w, map[string]any{"object": "error", "status": 400, "code": "validation_error", "message": "scripted failure"})
+				return
+			}
+			f.relations[id] = notion.ExtractStrings(req.Properties["People"])
+			f.write(t, w, map[string]any{"object": "page", "id": id})
+
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.Error(w, "unexpected", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// client returns a client of the fake that doesn't retry
+func (f *fakeNotion) client() *notion.Client {
+	return notion.NewClient("test-token", notion.WithBaseURL(f.URL), notion.WithMaxAttempts(1))
+}
+
+// query returns the batch of source pages starting at the request's cursor,
+// which is the index of its first page
+func (f *fakeNotion) query(req notion.QueryRequest) map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, req)
+	start := 0
+	if req.StartCursor != nil {
+		start, _ = strconv.Atoi(*req.StartCursor)
+	}
+	batch := f.batch
+	if batch == 0 {
+		batch = notion.DefaultPageSize
+	}
+	end := min(start+batch, len(f.sources))
+	var results []any
+	for _, src := range f.sources[start:end] {
+		results = append(results, map[string]any{"object": "page", "id": src.id, "properties": map[string]any{
+			"Name":   notion.TitleValue("Chronicle " + src.id),
+			"Who":    notion.RichTextValue(src.who),
+			"People": map[string]any{"id": "ppl", "type": "relation", "relation": []any{}},
+		}})
+	}
+	resp := map[string]any{"results": results, "has_more": end < len(f.sources), "next_cursor": nil}
+	if end < len(f.sources) {
+		resp["next_cursor"] = strconv.Itoa(end)
+	}
+	return resp
+}
+
+// find returns the ID of the oldest people page titled name
+func (f *fakeNotion) find(name string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, p := range f.people {
+		if p == name {
+			return fmt.Sprintf("person-%d", i), true
+		}
+	}
+	return "", false
+}
+
+// relationTitles returns the titles of the pages last written to pageID's relation
+func (f *fakeNotion) relationTitles(pageID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var titles []string
+	for _, id := range f.relations[pageID] {
+		var i int
+		if _, err := fmt.Sscanf(id, "person-%d", &i); err != nil || i >= len(f.people) {
+			titles = append(titles, id)
+			continue
+		}
+		titles = append(titles, f.people[i])
+	}
+	return titles
+}
+
+func (f *fakeNotion) page(id, name string) map[string]any {
+	return map[string]any{
+		"object":     "page",
+		"id":         id,
+		"properties": map[string]any{"Name": notion.TitleValue(name)},
+	}
+}
+
+func (f *fakeNotion) write(t *testing.T, w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Errorf("write response: %v", err)
+	}
+}
+
+// syncConfig returns the config syncing "src" into "people" with the defaults of parseFlags
+func syncConfig() config {
+	return config{
+		mode:          modeSync,
+		dataSource:    "src",
+		peopleDB:      "people",
+		peopleTitle:   "Name",
+		fields:        []string{"Who"},
+		separators:    []string{", "},
+		relationField: "People",
+		output:        outputText,
+		concurrency:   1,
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"notion-tools/internal/notion"
+)
+
+// importRow is a single CSV row converted to a page update
+type importRow struct {
+	line   int
+	pageID string
+	props  map[string]notion.PropertyValue
+}
+
+// runImportCSV updates pages from a CSV file where one column holds the page ID
+// and every other column maps to a property of the typed mapping, or else of
+// the data source schema.
+func runImportCSV(ctx context.Context, client *notion.Client, cfg config) error {
+	types, err := parseTypeMapping(cfg.types)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(cfg.file)
+	if err != nil {
+		return fmt.Errorf("open CSV: %w", err)
+	}
+	defer f.Close()
+
+	var schema *notion.Schema
+	getSchema := func() (*notion.Schema, error) {
+		if schema == nil {
+			s, err := client.GetDataSource(ctx, cfg.dataSource)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read data source schema: %w", err)
+			}
+			schema = s
+		}
+		return schema, nil
+	}
+	rows, err := readImportRows(f, cfg.idColumn, types, getSchema)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		sem    = make(chan struct{}, cfg.concurrency)
+	)
+	for _, row := range rows {
+		if cfg.dryRun {
+			infof("row %d: would update page %s: %s\n", row.line, row.pageID, describeProps(row.props))
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(row importRow) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := client.UpdatePage(ctx, row.pageID, row.props)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				errorf("row %d: failed to update page %s: %v\n", row.line, row.pageID, err)
+				return
+			}
+			infof("row %d: updated page %s\n", row.line, row.pageID)
+		}(row)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("import stopped early: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rows failed to import", failed, len(rows))
+	}
+	return nil
+}
+
+// parseTypeMapping parses "Column=type,Other=type" into a column to property type map
+func parseTypeMapping(s string) (map[string]string, error) {
+	types := map[string]string{}
+	if s == "" {
+		return types, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		name, typ, ok := strings.Cut(pair, "=")
+		name, typ = strings.TrimSpace(name), strings.TrimSpace(typ)
+		if !ok || name == "" || typ == "" {
+			return nil, fmt.Errorf("invalid type mapping %q: expected Column=type", pair)
+		}
+		if _, dup := types[name]; dup {
+			return nil, fmt.Errorf("duplicate type mapping for column %q", name)
+		}
+		types[name] = typ
+	}
+	return types, nil
+}
+
+// readImportRows reads the whole CSV and converts each row to property values.
+// Columns missing from types take the type of the property in the schema,
+// which is only fetched for them. Empty cells leave the corresponding
+// property untouched.
+func readImportRows(r io.Reader, idColumn string, types map[string]string, schema func() (*notion.Schema, error)) ([]importRow, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+
+	types = maps.Clone(types)
+	idIdx := -1
+	for i, col := range header {
+		col = strings.TrimSpace(col)
+		header[i] = col
+		if col == idColumn {
+			idIdx = i
+			continue
+		}
+		if _, ok := types[col]; ok {
+			continue
+		}
+		s, err := schema()
+		if err != nil {
+			return nil, err
+		}
+		ps, ok := s.Properties[col]
+		if !ok {
+			return nil, fmt.Errorf("no property type for CSV column %q: it is not in -types nor a property of data source %s", col, s.ID)
+		}
+		debugf("CSV column %q has type %q in the schema\n", col, ps.Type)
+		types[col] = ps.Type
+	}
+	if idIdx < 0 {
+		return nil, fmt.Errorf("CSV has no %q column", idColumn)
+	}
+
+	var rows []importRow
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV line %d: %w", line, err)
+		}
+
+		row := importRow{
+			line:   line,
+			pageID: strings.TrimSpace(rec[idIdx]),
+			props:  map[string]notion.PropertyValue{},
+		}
+		if row.pageID == "" {
+			return nil, fmt.Errorf("line %d: empty page ID", line)
+		}
+		for i, col := range header {
+			raw := strings.TrimSpace(rec[i])
+			if i == idIdx || raw == "" {
+				continue
+			}
+			v, err := buildPropertyValue(types[col], raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: column %q: %w", line, col, err)
+			}
+			row.props[col] = v
+		}
+		if len(row.props) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// buildPropertyValue converts a CSV cell to a property value of the given type.
+// Multi-valued types separate their values with ";".
+func buildPropertyValue(typ, raw string) (notion.PropertyValue, error) {
+	switch typ {
+	case "title":
+		return notion.TitleValue(raw), nil
+	case "rich_text":
+		return notion.RichTextValue(raw), nil
+	case "select":
+		return notion.SelectValue(raw), nil
+	case "multi_select":
+		return notion.MultiSelectValue(splitCell(raw)...), nil
+	case "number":
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return notion.PropertyValue{}, fmt.Errorf("invalid number %q", raw)
+		}
+		return notion.NumberValue(f), nil
+	case "checkbox":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return notion.PropertyValue{}, fmt.Errorf("invalid checkbox %q", raw)
+		}
+		return notion.CheckboxValue(b), nil
+	case "url":
+		return notion.ParseURLValue(raw)
+	case "email":
+		return notion.ParseEmailValue(raw)
+	case "phone_number":
+		return notion.ParsePhoneNumberValue(raw)
+	case "relation":
+		return notion.RelationValue(splitCell(raw)...), nil
+	case "people":
+		return notion.PeopleValue(splitCell(raw)...), nil
+	case "date":
+		start, end, _ := strings.Cut(raw, "/")
+		return notion.DateStringValue(strings.TrimSpace(start), strings.TrimSpace(end)), nil
+	default:
+		return notion.PropertyValue{}, fmt.Errorf("unsupported property type %q", typ)
+	}
+}
+
+func splitCell(raw string) []string {
+	var out []string
+	for _, v := range strings.Split(raw, ";") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func describeProps(props map[string]notion.PropertyValue) string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%q", name, strings.Join(notion.ExtractStrings(props[name]), "; ")))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,23 @@
+package notion
+
+import (
+	"context"
+	"net/url"
+)
+
+// PageAPI is the part of the API that syncing pages between data sources
+// needs. *Client implements it; code depending on PageAPI instead can be
+// exercised without the network by passing a fake.
+type PageAPI interface {
+	GetDataSource(ctx context.Context, dataSourceID string) (*Schema, error)
+	QueryEach(ctx context.Context, dataSourceID string, req QueryRequest, qp url.Values, fn func(Page) error) error
+	QueryBatches(ctx context.Context, dataSourceID string, req QueryRequest, qp url.Values, fn func(*QueryResponse) error) error
+	FindPageByTitle(ctx context.Context, datasourceID, titleProperty, title string) (*Page, error)
+	GetPage(ctx context.Context, pageID string, properties ...string) (*Page, error)
+	GetFullProperty(ctx context.Context, pageID, propertyID string) (PropertyValue, error)
+	CreatePageFull(ctx context.Context, datasourceID string, properties map[string]PropertyValue, content PageContent) (*Page, error)
+	UpdatePage(ctx context.Context, pageID string, properties map[string]PropertyValue) error
+	UpsertPersonByTitle(ctx context.Context, dataSourceID, titleProperty, name string, props map[string]PropertyValue, content PageContent) (*Page, bool, error)
+}
+
+var _ PageAPI = (*Client)(nil)
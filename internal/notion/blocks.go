@@ -0,0 +1,187 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Block is a piece of page content. Only the field matching Type is set;
+// block types not modeled here keep just their Type.
+type Block struct {
+	Object           string        `json:"object,omitempty"`
+	ID               string        `json:"id,omitempty"`
+	Type             string        `json:"type"`
+	HasChildren      bool          `json:"has_children,omitempty"`
+	Paragraph        *TextBlock    `json:"paragraph,omitempty"`
+	Heading1         *HeadingBlock `json:"heading_1,omitempty"`
+	Heading2         *HeadingBlock `json:"heading_2,omitempty"`
+	Heading3         *HeadingBlock `json:"heading_3,omitempty"`
+	BulletedListItem *TextBlock    `json:"bulleted_list_item,omitempty"`
+	NumberedListItem *TextBlock    `json:"numbered_list_item,omitempty"`
+	ToDo             *ToDoBlock    `json:"to_do,omitempty"`
+	Code             *CodeBlock    `json:"code,omitempty"`
+	Quote            *TextBlock    `json:"quote,omitempty"`
+	Toggle           *TextBlock    `json:"toggle,omitempty"`
+}
+
+// TextBlock is the content of paragraph, list item, quote and toggle blocks
+type TextBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Color    string     `json:"color,omitempty"`
+}
+
+// HeadingBlock is the content of heading_1, heading_2 and heading_3 blocks
+type HeadingBlock struct {
+	RichText     []RichText `json:"rich_text"`
+	Color        string     `json:"color,omitempty"`
+	IsToggleable bool       `json:"is_toggleable,omitempty"`
+}
+
+// ToDoBlock is the content of a to_do block
+type ToDoBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Checked  bool       `json:"checked"`
+	Color    string     `json:"color,omitempty"`
+}
+
+// CodeBlock is the content of a code block
+type CodeBlock struct {
+	RichText []RichText `json:"rich_text"`
+	Caption  []RichText `json:"caption,omitempty"`
+	Language string     `json:"language"`
+}
+
+// RichText returns the text of the block, or nil for block types without text
+func (b Block) RichText() []RichText {
+	switch {
+	case b.Paragraph != nil:
+		return b.Paragraph.RichText
+	case b.Heading1 != nil:
+		return b.Heading1.RichText
+	case b.Heading2 != nil:
+		return b.Heading2.RichText
+	case b.Heading3 != nil:
+		return b.Heading3.RichText
+	case b.BulletedListItem != nil:
+		return b.BulletedListItem.RichText
+	case b.NumberedListItem != nil:
+		return b.NumberedListItem.RichText
+	case b.ToDo != nil:
+		return b.ToDo.RichText
+	case b.Code != nil:
+		return b.Code.RichText
+	case b.Quote != nil:
+		return b.Quote.RichText
+	case b.Toggle != nil:
+		return b.Toggle.RichText
+	}
+	return nil
+}
+
+// BlockList is a page of child blocks
+type BlockList struct {
+	Object     string  `json:"object"`
+	Results    []Block `json:"results"`
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// More reports whether another page of results can be fetched
+func (r BlockList) More() bool {
+	return hasNextCursor(r.HasMore, r.NextCursor)
+}
+
+// Cursor returns the start cursor for the next page of results, or nil when there is none
+func (r BlockList) Cursor() *string {
+	if !r.More() {
+		return nil
+	}
+	return r.NextCursor
+}
+
+// CheckCursor returns ErrMissingCursor when HasMore is set without a usable cursor
+func (r BlockList) CheckCursor() error {
+	return checkCursor(r.HasMore, r.NextCursor)
+}
+
+// GetBlockChildren retrieves one page of the children of a block or page,
+// starting at cursor, or at the first child when cursor is empty
+func (c *Client) GetBlockChildren(ctx context.Context, blockID, cursor string) (*BlockList, error) {
+	q := url.Values{"page_size": {strconv.Itoa(DefaultPageSize)}}
+	if cursor != "" {
+		q.Set("start_cursor", cursor)
+	}
+	var resp BlockList
+	if err := c.Do(ctx, http.MethodGet, "/blocks/"+blockID+"/children", q, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetAllBlockChildren retrieves every direct child of a block or page,
+// following next_cursor until exhausted. Nested children are not fetched.
+func (c *Client) GetAllBlockChildren(ctx context.Context, blockID string) ([]Block, error) {
+	var blocks []Block
+	cursor := ""
+	seen := map[string]bool{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("list children of block %s: %w", blockID, err)
+		}
+		resp, err := c.GetBlockChildren(ctx, blockID, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("list children of block %s: %w", blockID, err)
+		}
+		blocks = append(blocks, resp.Results...)
+
+		if err := resp.CheckCursor(); err != nil {
+			return nil, fmt.Errorf("list children of block %s: %w", blockID, err)
+		}
+		if !resp.More() {
+			return blocks, nil
+		}
+		cursor = *resp.Cursor()
+		if seen[cursor] {
+			return nil, fmt.Errorf("list children of block %s: next cursor %q was already returned", blockID, cursor)
+		}
+		seen[cursor] = true
+	}
+}
+
+// MaxAppendChildren is the most children Notion accepts in one append request
+const MaxAppendChildren = 100
+
+// AppendBlocksRequest appends children to a block or page
+type AppendBlocksRequest struct {
+	Children []Block `json:"children"`
+}
+
+// AppendBlockChildren appends blocks to the end of a block or page and returns
+// the created blocks. More than MaxAppendChildren blocks are sent in several
+// requests, in order; when one fails the blocks created so far are returned with the error.
+func (c *Client) AppendBlockChildren(ctx context.Context, blockID string, blocks []Block) ([]Block, error) {
+	var created []Block
+	for start := 0; start < len(blocks); start += MaxAppendChildren {
+		end := min(start+MaxAppendChildren, len(blocks))
+		var resp BlockList
+		req := AppendBlocksRequest{Children: blocks[start:end]}
+		if err := c.Do(ctx, http.MethodPatch, "/blocks/"+blockID+"/children", nil, req, &resp); err != nil {
+			return created, fmt.Errorf("append children %d-%d to block %s: %w", start, end-1, blockID, err)
+		}
+		created = append(created, resp.Results...)
+	}
+	return created, nil
+}
+
+// ParagraphBlock builds a paragraph block of plain text
+func ParagraphBlock(text string) Block {
+	return Block{Type: "paragraph", Paragraph: &TextBlock{RichText: textRichText(text)}}
+}
+
+// Heading2Block builds a heading_2 block of plain text
+func Heading2Block(text string) Block {
+	return Block{Type: "heading_2", Heading2: &HeadingBlock{RichText: textRichText(text)}}
+}
@@ -0,0 +1,108 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Database is a database container. Since API version 2025-09-03 its rows
+// live in one or more data sources, which are what pages are queried from and
+// created in.
+type Database struct {
+	Object         string          `json:"object"`
+	ID             string          `json:"id"`
+	Title          []RichText      `json:"title,omitempty"`
+	DataSources    []DataSourceRef `json:"data_sources"`
+	LastEditedTime string          `json:"last_edited_time,omitempty"`
+}
+
+// DataSourceRef names a data source of a database
+type DataSourceRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// DataSourcesVersion is the first API version with data sources. A client
+// pinned to an earlier one with WithNotionVersion is Legacy.
+const DataSourcesVersion = "2025-09-03"
+
+// Legacy reports whether the client's API version predates data sources. A
+// legacy client takes database IDs wherever data source IDs are asked for: it
+// reads their schema from /databases/{id}, queries them like QueryDatabase
+// and creates pages with a database parent.
+func (c *Client) Legacy() bool {
+	return c.version < DataSourcesVersion
+}
+
+// QueryPath returns the endpoint querying dataSourceID, a database's on a
+// Legacy client
+func (c *Client) QueryPath(dataSourceID string) string {
+	if c.Legacy() {
+		return "/databases/" + dataSourceID + "/query"
+	}
+	return "/data_sources/" + dataSourceID + "/query"
+}
+
+// GetDatabase retrieves a database and the data sources it contains
+func (c *Client) GetDatabase(ctx context.Context, databaseID string) (*Database, error) {
+	var resp Database
+	if err := c.Do(ctx, http.MethodGet, "/databases/"+databaseID, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Parent kinds accepted by ResolveDataSource
+const (
+	ParentAuto       = "auto"
+	ParentDataSource = "data_source"
+	ParentDatabase   = "database"
+)
+
+// ResolveDataSource returns the data source ID to use for id, which is of the
+// given kind. A database resolves to its data source and fails if it has
+// several, since which one to use is ambiguous. ParentAuto tries id as a data
+// source first and falls back to a database when Notion doesn't find it; an
+// empty kind means ParentAuto. A Legacy client has no data sources and uses
+// database IDs as they are.
+func (c *Client) ResolveDataSource(ctx context.Context, id, kind string) (string, error) {
+	if c.Legacy() {
+		if kind == ParentDataSource {
+			return "", fmt.Errorf("API version %s has no data sources, pass the database ID of %s", c.version, id)
+		}
+		return id, nil
+	}
+	switch kind {
+	case ParentDataSource:
+		return id, nil
+	case ParentAuto, "":
+		_, err := c.GetDataSource(ctx, id)
+		if err == nil {
+			return id, nil
+		}
+		if !HasStatus(err, http.StatusNotFound) && !HasStatus(err, http.StatusBadRequest) {
+			return "", err
+		}
+	case ParentDatabase:
+	default:
+		return "", fmt.Errorf("unknown parent kind %q: expected %q, %q or %q", kind, ParentAuto, ParentDataSource, ParentDatabase)
+	}
+
+	db, err := c.GetDatabase(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s as a database: %w", id, err)
+	}
+	switch len(db.DataSources) {
+	case 0:
+		return "", fmt.Errorf("database %s has no data sources", id)
+	case 1:
+		return db.DataSources[0].ID, nil
+	}
+	refs := make([]string, len(db.DataSources))
+	for i, ds := range db.DataSources {
+		refs[i] = fmt.Sprintf("%s (%s)", ds.ID, ds.Name)
+	}
+	return "", fmt.Errorf("database %s has %d data sources, pass one of them: %s", id, len(db.DataSources), strings.Join(refs, ", "))
+}
@@ -0,0 +1,63 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoDate is returned by ParseDate when the property holds no date
+var ErrNoDate = errors.New("no date set")
+
+// ParseDate parses the date held by a date property, or by a formula or
+// rollup that evaluates to a date. Both date-only values ("2024-01-02", parsed
+// as midnight UTC) and RFC 3339 timestamps with offsets are accepted. end is
+// nil unless the date is a range. ErrNoDate distinguishes an empty value from
+// a parse error.
+func ParseDate(p PropertyValue) (start time.Time, end *time.Time, err error) {
+	d := dateOf(p)
+	if d == nil || d.Start == "" {
+		return time.Time{}, nil, ErrNoDate
+	}
+
+	start, err = parseDateString(d.Start)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if d.End != nil && *d.End != "" {
+		e, err := parseDateString(*d.End)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		end = &e
+	}
+	return start, end, nil
+}
+
+// dateOf returns the date value carried by a date, formula or rollup property
+func dateOf(p PropertyValue) *DateValue {
+	switch p.Type {
+	case "date":
+		return p.Date
+	case "formula":
+		if p.Formula != nil && p.Formula.Type == "date" {
+			return p.Formula.Date
+		}
+	case "rollup":
+		if p.Rollup != nil && p.Rollup.Type == "date" {
+			return p.Rollup.Date
+		}
+	}
+	return nil
+}
+
+func parseDateString(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse date %q: expected YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,225 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// multiValued reports whether p can hold several values, which only decode
+// into string slices
+func multiValued(p PropertyValue) bool {
+	switch p.Type {
+	case "multi_select", "people", "relation", "files":
+		return true
+	case "rollup":
+		return p.Rollup != nil && p.Rollup.Type == "array"
+	}
+	return false
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// DecodePage fills the struct dest points to from the page's properties,
+// much like json.Unmarshal. Fields are matched by their notion tag, e.g.
+//
+//	type Chronicle struct {
+//		ID    string    `notion:"-id"`
+//		Title string    `notion:"Name"`
+//		Who   []string  `notion:"Who"`
+//		Score *float64  `notion:"Score"`
+//		Date  time.Time `notion:"Date"`
+//	}
+//
+// Untagged fields and fields tagged "-" are left alone, and the tag "-id"
+// receives the page ID. By field type:
+//   - string: any single-valued property, as ExtractString renders it
+//   - []string: any property, as ExtractStrings renders it
+//   - float64, float32 and integer kinds: number, number formula or unique_id
+//   - bool: checkbox or boolean formula
+//   - time.Time: the start of a date, or a created_time or last_edited_time
+//
+// A pointer to one of these is left nil when the property is empty. A tagged
+// property the page lacks, or whose type does not fit the field, is an error.
+func DecodePage(page Page, dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode page: destination must be a non-nil pointer to a struct, got %T", dest)
+	}
+
+	sv := rv.Elem()
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		name, ok := f.Tag.Lookup("notion")
+		if !ok || name == "-" || !f.IsExported() {
+			continue
+		}
+
+		if name == "-id" {
+			if f.Type.Kind() != reflect.String {
+				return fmt.Errorf("decode page %s: field %s: page ID needs a string field, got %s", page.ID, f.Name, f.Type)
+			}
+			sv.Field(i).SetString(page.ID)
+			continue
+		}
+
+		p, ok := page.Properties[name]
+		if !ok {
+			return fmt.Errorf("decode page %s: field %s: property %q not found", page.ID, f.Name, name)
+		}
+		if err := decodeProperty(sv.Field(i), p); err != nil {
+			return fmt.Errorf("decode page %s: field %s: property %q: %w", page.ID, f.Name, name, err)
+		}
+	}
+	return nil
+}
+
+// errEmpty reports a property without a value; the field is then zeroed
+var errEmpty = errors.New("empty")
+
+func decodeProperty(fv reflect.Value, p PropertyValue) error {
+	if fv.Kind() != reflect.Pointer {
+		err := decodeValue(fv, p)
+		if errors.Is(err, errEmpty) {
+			fv.Set(reflect.Zero(fv.Type()))
+			return nil
+		}
+		return err
+	}
+
+	v := reflect.New(fv.Type().Elem())
+	err := decodeValue(v.Elem(), p)
+	switch {
+	case errors.Is(err, errEmpty):
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	case err != nil:
+		return err
+	}
+	fv.Set(v)
+	return nil
+}
+
+// decodeValue stores p in fv, returning errEmpty when p has no value
+func decodeValue(fv reflect.Value, p PropertyValue) error {
+	mismatch := fmt.Errorf("type %q cannot be decoded into %s", p.Type, fv.Type())
+
+	if fv.Type() == timeType {
+		var s string
+		switch p.Type {
+		case "created_time", "last_edited_time":
+			s = ExtractString(p)
+		default:
+			d := dateOf(p)
+			if d == nil && p.Type != "date" {
+				return mismatch
+			}
+			if d != nil {
+				s = d.Start
+			}
+		}
+		if s == "" {
+			return errEmpty
+		}
+		t, err := parseDateString(s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		if multiValued(p) {
+			return fmt.Errorf("%w; use a []string field", mismatch)
+		}
+		s := ExtractString(p)
+		if s == "" {
+			return errEmpty
+		}
+		fv.SetString(s)
+
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return mismatch
+		}
+		values := ExtractStrings(p)
+		if len(values) == 0 {
+			return errEmpty
+		}
+		out := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, s := range values {
+			out.Index(i).SetString(s)
+		}
+		fv.Set(out)
+
+	case reflect.Bool:
+		var b *bool
+		switch {
+		case p.Type == "checkbox":
+			b = p.Checkbox
+		case p.Type == "formula" && p.Formula != nil && p.Formula.Type == "boolean":
+			b = p.Formula.Boolean
+		default:
+			return mismatch
+		}
+		if b == nil {
+			return errEmpty
+		}
+		fv.SetBool(*b)
+
+	case reflect.Float32, reflect.Float64:
+		n, ok := numberValue(p)
+		if !ok {
+			return mismatch
+		}
+		if n == nil {
+			return errEmpty
+		}
+		fv.SetFloat(*n)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := numberValue(p)
+		if !ok {
+			return mismatch
+		}
+		if n == nil {
+			return errEmpty
+		}
+		// Converting a float outside the int64 range is implementation-defined,
+		// so the range is checked before the conversion.
+		if *n < math.MinInt64 || *n >= math.MaxInt64 || math.Trunc(*n) != *n || fv.OverflowInt(int64(*n)) {
+			return fmt.Errorf("number %v does not fit %s", *n, fv.Type())
+		}
+		fv.SetInt(int64(*n))
+
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
+
+// numberValue returns the number held by a number, number formula or
+// unique_id property, nil when it is empty; ok is false for other properties
+func numberValue(p PropertyValue) (n *float64, ok bool) {
+	switch p.Type {
+	case "number":
+		return p.Number, true
+	case "unique_id":
+		if p.UniqueID == nil {
+			return nil, true
+		}
+		f := float64(p.UniqueID.Number)
+		return &f, true
+	case "formula":
+		if p.Formula == nil {
+			return nil, true
+		}
+		return numberOf(p), p.Formula.Type == "number"
+	}
+	return nil, false
+}
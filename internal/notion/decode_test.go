@@ -0,0 +1,141 @@
+package notion
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeProps are the properties of the page the DecodePage tests decode
+func decodeProps() map[string]PropertyValue {
+	num := func(f float64) PropertyValue { return NumberValue(f) }
+	created := "2024-01-02T03:04:05Z"
+	date := DateTimeValue(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), nil)
+	return map[string]PropertyValue{
+		"Name":    TitleValue("Alice"),
+		"Tags":    MultiSelectValue("a", "b"),
+		"Score":   num(2.5),
+		"Count":   num(42),
+		"Empty":   {Type: "number"},
+		"Big":     num(300),
+		"Huge":    num(math.MaxInt64),
+		"Done":    CheckboxValue(true),
+		"Date":    date,
+		"NoDate":  {Type: "date"},
+		"Created": {Type: "created_time", CreatedTime: &created},
+		"Ticket":  {Type: "unique_id", UniqueID: &UniqueIDValue{Number: 7}},
+	}
+}
+
+func TestDecodePage(t *testing.T) {
+	props := decodeProps()
+	score, count := 2.5, 42
+
+	type basic struct {
+		ID      string    `notion:"-id"`
+		Title   string    `notion:"Name"`
+		Tags    []string  `notion:"Tags"`
+		Score   float64   `notion:"Score"`
+		Count   int       `notion:"Count"`
+		Done    bool      `notion:"Done"`
+		Date    time.Time `notion:"Date"`
+		Created time.Time `notion:"Created"`
+		Ticket  int64     `notion:"Ticket"`
+		Skipped string    `notion:"-"`
+		Plain   string
+	}
+	type pointers struct {
+		Score *float64   `notion:"Score"`
+		Count *int       `notion:"Count"`
+		Empty *float64   `notion:"Empty"`
+		Date  *time.Time `notion:"NoDate"`
+		Tags  *[]string  `notion:"Tags"`
+	}
+	tests := []struct {
+		name    string
+		dest    any // pointer to a zero struct to decode into
+		want    any // the decoded struct; nil when an error is expected
+		wantErr string
+	}{
+		{
+			name: "every field kind",
+			dest: &basic{Skipped: "kept", Plain: "kept"},
+			want: &basic{
+				ID: "page-1", Title: "Alice", Tags: []string{"a", "b"}, Score: 2.5, Count: 42, Done: true,
+				Date: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Ticket: 7, Skipped: "kept", Plain: "kept",
+			},
+		},
+		{
+			name: "pointers set or left nil",
+			dest: &pointers{},
+			want: &pointers{Score: &score, Count: &count, Tags: &[]string{"a", "b"}},
+		},
+		{
+			name:    "not a pointer",
+			dest:    basic{},
+			wantErr: "destination must be a non-nil pointer to a struct",
+		},
+		{
+			name:    "pointer to non-struct",
+			dest:    new(string),
+			wantErr: "destination must be a non-nil pointer to a struct",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DecodePage(Page{ID: "page-1", Properties: props}, tt.dest)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.dest, tt.want) {
+				t.Errorf("decoded %+v, want %+v", tt.dest, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePageFieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   any    // a value of the type of the struct's only field
+		tag     string // its notion tag
+		wantErr string
+	}{
+		{"property the page lacks", "", "Missing", `property "Missing" not found`},
+		{"multi-valued into string", "", "Tags", "use a []string field"},
+		{"text into number", 0.0, "Name", `type "title" cannot be decoded into float64`},
+		{"number into bool", false, "Score", `type "number" cannot be decoded into bool`},
+		{"checkbox into time", time.Time{}, "Done", `type "checkbox" cannot be decoded into time.Time`},
+		{"slice of non-strings", []int(nil), "Tags", "cannot be decoded into []int"},
+		{"unsupported field type", map[string]string(nil), "Name", "unsupported field type map[string]string"},
+		{"page ID into non-string", 0, "-id", "page ID needs a string field"},
+		{"fraction into int", 0, "Score", "number 2.5 does not fit int"},
+		{"overflows int8", int8(0), "Big", "number 300 does not fit int8"},
+		{"outside int64", int64(0), "Huge", "does not fit int64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ := reflect.StructOf([]reflect.StructField{{
+				Name: "X",
+				Type: reflect.TypeOf(tt.field),
+				Tag:  reflect.StructTag(`notion:"` + tt.tag + `"`),
+			}})
+			err := DecodePage(Page{ID: "page-1", Properties: decodeProps()}, reflect.New(typ).Interface())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), "field X") {
+				t.Errorf("error %q does not name the field", err)
+			}
+		})
+	}
+}
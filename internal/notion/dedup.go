@@ -0,0 +1,99 @@
+package notion
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// flightGroup collapses concurrent calls with the same key into one.
+// Results are shared only with callers waiting at the time; nothing is retained.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	wg  sync.WaitGroup
+	val response
+	err error
+}
+
+// do runs fn, or waits for the call with the same key already running.
+// shared reports whether the result came from another caller's call.
+func (g *flightGroup) do(key string, fn func() (response, error)) (r response, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, true, c.err
+	}
+	c := &flightCall{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.val, c.err = fn()
+	c.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	return c.val, false, c.err
+}
+
+// readCache keeps successful read responses until the next write. Each
+// write starts a new generation; a response read in an older generation may
+// predate the write and is not kept.
+type readCache struct {
+	mu      sync.Mutex
+	entries map[string]response
+	gen     uint64
+}
+
+func (rc *readCache) get(key string) (response, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	b, ok := rc.entries[key]
+	return b, ok
+}
+
+// generation returns the current generation, to pass to put
+func (rc *readCache) generation() uint64 {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.gen
+}
+
+// put keeps r for key if no write happened since generation gen
+func (rc *readCache) put(key string, r response, gen uint64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if gen != rc.gen {
+		return
+	}
+	if rc.entries == nil {
+		rc.entries = map[string]response{}
+	}
+	rc.entries[key] = r
+}
+
+// clear drops every entry and starts a new generation
+func (rc *readCache) clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = nil
+	rc.gen++
+}
+
+// isRead reports whether a request only reads data. Queries and searches are
+// POSTs but have no side effects.
+func isRead(method, path string) bool {
+	if method == http.MethodGet {
+		return true
+	}
+	return method == http.MethodPost && (strings.HasSuffix(path, "/query") || path == "/search")
+}
@@ -0,0 +1,151 @@
+package notion
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingServer serves a page whose title a PATCH sets to "after", counting
+// the GETs that reach it
+type countingServer struct {
+	gets  atomic.Int32
+	title atomic.Value // string
+
+	// get, when set, runs after a GET has read the title and before it is answered
+	get func()
+}
+
+func (s *countingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	title, _ := s.title.Load().(string)
+	switch r.Method {
+	case http.MethodGet:
+		s.gets.Add(1)
+		if s.get != nil {
+			s.get()
+		}
+	case http.MethodPatch:
+		title = "after"
+		s.title.Store(title)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"object":"page","id":"page-1","properties":{"Name":{"type":"title","title":[{"type":"text","text":{"content":%q},"plain_text":%q}]}}}`, title, title)
+}
+
+func TestConcurrentReadsShareOneRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		pages    []string // page IDs read concurrently
+		wantGets int32
+	}{
+		{"identical", []string{"page-1", "page-1", "page-1", "page-1"}, 1},
+		{"different", []string{"page-1", "page-2"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			srv := &countingServer{get: func() { <-release }}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+			client := NewClient("test-token", WithBaseURL(ts.URL))
+
+			var wg sync.WaitGroup
+			errs := make([]error, len(tt.pages))
+			for i, id := range tt.pages {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = client.GetPage(t.Context(), id)
+				}()
+			}
+			// Let every read reach the client before the first one returns.
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			for i, err := range errs {
+				if err != nil {
+					t.Errorf("read %d: %v", i, err)
+				}
+			}
+			if got := srv.gets.Load(); got != tt.wantGets {
+				t.Errorf("%d GETs reached the server, want %d", got, tt.wantGets)
+			}
+		})
+	}
+}
+
+func TestReadCacheSkipsErrors(t *testing.T) {
+	fail := true
+	gets := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		if fail {
+			fail = false
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"object":"error","status":500,"code":"internal_server_error","message":"boom"}`))
+			return
+		}
+		w.Write([]byte(`{"object":"page","id":"page-1"}`))
+	}))
+	defer ts.Close()
+	client := NewClient("test-token", WithBaseURL(ts.URL), WithReadCache(), WithMaxAttempts(1))
+
+	if _, err := client.GetPage(t.Context(), "page-1"); err == nil {
+		t.Fatal("first read succeeded, want the server's error")
+	}
+	for range 2 {
+		if _, err := client.GetPage(t.Context(), "page-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The error was not cached, the success was.
+	if gets != 2 {
+		t.Errorf("%d GETs reached the server, want 2", gets)
+	}
+}
+
+func TestReadCacheDropsReadsRacingAWrite(t *testing.T) {
+	arrived, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	srv := &countingServer{get: func() {
+		// Hold the first read, which has seen the title from before the write, until the write is done.
+		once.Do(func() {
+			close(arrived)
+			<-release
+		})
+	}}
+	srv.title.Store("before")
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	client := NewClient("test-token", WithBaseURL(ts.URL), WithReadCache())
+
+	done := make(chan error)
+	go func() {
+		_, err := client.GetPage(t.Context(), "page-1")
+		done <- err
+	}()
+	<-arrived
+	if err := client.UpdatePage(t.Context(), "page-1", map[string]PropertyValue{"Name": TitleValue("after")}); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	pg, err := client.GetPage(t.Context(), "page-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ExtractString(pg.Properties["Name"]); got != "after" {
+		t.Errorf("read after the write = %q, want %q", got, "after")
+	}
+	if got := srv.gets.Load(); got != 2 {
+		t.Errorf("%d GETs reached the server, want 2", got)
+	}
+}
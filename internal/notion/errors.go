@@ -0,0 +1,111 @@
+package notion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// maxErrorBody is how much of a response body error messages include
+const maxErrorBody = 512
+
+// APIError is returned when the Notion API responds with a non-2xx status.
+// Use errors.As to inspect it.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Code is Notion's error code, e.g. "object_not_found" or "unauthorized"
+	Code string
+	// Message is Notion's human readable error message
+	Message string
+	// Body is the raw response body
+	Body string
+	// RetryAfter is the delay requested by the Retry-After header, if any
+	RetryAfter time.Duration
+	// RequestID identifies the request to Notion support; it is empty when
+	// neither the error body nor the headers carried one
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	var msg string
+	if e.Code == "" && e.Message == "" {
+		msg = fmt.Sprintf("notion API %s %s failed: status=%d body=%s", e.Method, e.Path, e.StatusCode, TruncateBody(e.Body))
+	} else {
+		msg = fmt.Sprintf("notion API %s %s failed: status=%d code=%s message=%s", e.Method, e.Path, e.StatusCode, e.Code, e.Message)
+	}
+	if e.RequestID != "" {
+		msg += " request_id=" + e.RequestID
+	}
+	return msg
+}
+
+// DecodeError is returned when a successful response cannot be decoded.
+// Its message shows only the start of the body; Body holds all of it.
+type DecodeError struct {
+	Method string
+	Path   string
+	Body   string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("unmarshal response of %s %s: %v (body=%s)", e.Method, e.Path, e.Err, TruncateBody(e.Body))
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// TruncateBody shortens a response body to maxErrorBody bytes for error
+// messages, without splitting a character, and marks what was cut
+func TruncateBody(body string) string {
+	if len(body) <= maxErrorBody {
+		return body
+	}
+	cut := maxErrorBody
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s… (%d bytes total)", body[:cut], len(body))
+}
+
+// errorBody is the JSON error object returned by Notion
+type errorBody struct {
+	Object    string `json:"object"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+}
+
+// newAPIError builds an APIError, taking Code and Message from the JSON error
+// body when it parses; otherwise only the raw body is kept. The body's
+// request_id is preferred over the header's.
+func newAPIError(method, path string, meta ResponseMeta, body string) *APIError {
+	e := &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: meta.StatusCode,
+		Body:       body,
+		RetryAfter: meta.RetryAfter,
+		RequestID:  meta.RequestID,
+	}
+	var eb errorBody
+	if json.Unmarshal([]byte(body), &eb) == nil && eb.Object == "error" {
+		e.Code = eb.Code
+		e.Message = eb.Message
+		if eb.RequestID != "" {
+			e.RequestID = eb.RequestID
+		}
+	}
+	return e
+}
+
+// HasStatus reports whether err is an APIError with the given HTTP status code
+func HasStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
@@ -0,0 +1,236 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Filter is a data source query filter: either a condition on one property
+// (or page timestamp), or a compound And/Or of other filters. Compound filters
+// nest, e.g. And(Or(StatusEquals(...), StatusEquals(...)), DateBefore(...)),
+// up to MaxFilterDepth levels.
+//
+// Supported property conditions: title, rich_text, select, status,
+// multi_select, checkbox and date, plus the created_time and last_edited_time
+// page timestamps.
+type Filter struct {
+	Property  string `json:"property,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+
+	Title          *TextCondition        `json:"title,omitempty"`
+	RichText       *TextCondition        `json:"rich_text,omitempty"`
+	Select         *SelectCondition      `json:"select,omitempty"`
+	Status         *SelectCondition      `json:"status,omitempty"`
+	MultiSelect    *MultiSelectCondition `json:"multi_select,omitempty"`
+	Checkbox       *CheckboxCondition    `json:"checkbox,omitempty"`
+	Date           *DateCondition        `json:"date,omitempty"`
+	CreatedTime    *DateCondition        `json:"created_time,omitempty"`
+	LastEditedTime *DateCondition        `json:"last_edited_time,omitempty"`
+
+	And []*Filter `json:"and,omitempty"`
+	Or  []*Filter `json:"or,omitempty"`
+}
+
+// TextCondition filters title and rich_text properties. Empty strings are
+// left out of the request, so a condition such as equals "" cannot be
+// expressed; match empty text with IsEmpty instead.
+type TextCondition struct {
+	Equals         string `json:"equals,omitempty"`
+	DoesNotEqual   string `json:"does_not_equal,omitempty"`
+	Contains       string `json:"contains,omitempty"`
+	DoesNotContain string `json:"does_not_contain,omitempty"`
+	StartsWith     string `json:"starts_with,omitempty"`
+	EndsWith       string `json:"ends_with,omitempty"`
+	IsEmpty        bool   `json:"is_empty,omitempty"`
+	IsNotEmpty     bool   `json:"is_not_empty,omitempty"`
+}
+
+// SelectCondition filters select and status properties
+type SelectCondition struct {
+	Equals       string `json:"equals,omitempty"`
+	DoesNotEqual string `json:"does_not_equal,omitempty"`
+	IsEmpty      bool   `json:"is_empty,omitempty"`
+	IsNotEmpty   bool   `json:"is_not_empty,omitempty"`
+}
+
+// MultiSelectCondition filters multi_select properties
+type MultiSelectCondition struct {
+	Contains       string `json:"contains,omitempty"`
+	DoesNotContain string `json:"does_not_contain,omitempty"`
+	IsEmpty        bool   `json:"is_empty,omitempty"`
+	IsNotEmpty     bool   `json:"is_not_empty,omitempty"`
+}
+
+// CheckboxCondition filters checkbox properties
+type CheckboxCondition struct {
+	Equals *bool `json:"equals,omitempty"`
+}
+
+// DateCondition filters date properties and page timestamps.
+// Values are ISO 8601 dates or date-times.
+type DateCondition struct {
+	Equals     string `json:"equals,omitempty"`
+	Before     string `json:"before,omitempty"`
+	After      string `json:"after,omitempty"`
+	OnOrBefore string `json:"on_or_before,omitempty"`
+	OnOrAfter  string `json:"on_or_after,omitempty"`
+	IsEmpty    bool   `json:"is_empty,omitempty"`
+	IsNotEmpty bool   `json:"is_not_empty,omitempty"`
+}
+
+// TitleEquals matches pages whose title property equals s, which must not be
+// empty; see TextCondition
+func TitleEquals(property, s string) *Filter {
+	return &Filter{Property: property, Title: &TextCondition{Equals: s}}
+}
+
+// SelectEquals matches pages whose select property is set to option
+func SelectEquals(property, option string) *Filter {
+	return &Filter{Property: property, Select: &SelectCondition{Equals: option}}
+}
+
+// StatusEquals matches pages whose status property is set to option
+func StatusEquals(property, option string) *Filter {
+	return &Filter{Property: property, Status: &SelectCondition{Equals: option}}
+}
+
+// MultiSelectContains matches pages whose multi_select property includes option
+func MultiSelectContains(property, option string) *Filter {
+	return &Filter{Property: property, MultiSelect: &MultiSelectCondition{Contains: option}}
+}
+
+// CheckboxIs matches pages whose checkbox property is b
+func CheckboxIs(property string, b bool) *Filter {
+	return &Filter{Property: property, Checkbox: &CheckboxCondition{Equals: &b}}
+}
+
+// DateBefore matches pages whose date property is before t
+func DateBefore(property string, t time.Time) *Filter {
+	return &Filter{Property: property, Date: &DateCondition{Before: t.Format(time.RFC3339)}}
+}
+
+// DateAfter matches pages whose date property is after t
+func DateAfter(property string, t time.Time) *Filter {
+	return &Filter{Property: property, Date: &DateCondition{After: t.Format(time.RFC3339)}}
+}
+
+// LastEditedOnOrAfter matches pages last edited at or after t
+func LastEditedOnOrAfter(t time.Time) *Filter {
+	return &Filter{
+		Timestamp:      "last_edited_time",
+		LastEditedTime: &DateCondition{OnOrAfter: t.UTC().Format(time.RFC3339)},
+	}
+}
+
+// And matches pages matching every filter
+func And(filters ...*Filter) *Filter {
+	return &Filter{And: filters}
+}
+
+// Or matches pages matching any of the filters
+func Or(filters ...*Filter) *Filter {
+	return &Filter{Or: filters}
+}
+
+// StatusIn matches pages whose status property is set to any of the options
+func StatusIn(property string, options ...string) *Filter {
+	filters := make([]*Filter, 0, len(options))
+	for _, o := range options {
+		filters = append(filters, StatusEquals(property, o))
+	}
+	return Or(filters...)
+}
+
+// SelectIn matches pages whose select property is set to any of the options
+func SelectIn(property string, options ...string) *Filter {
+	filters := make([]*Filter, 0, len(options))
+	for _, o := range options {
+		filters = append(filters, SelectEquals(property, o))
+	}
+	return Or(filters...)
+}
+
+// MaxFilterDepth is how many levels of compound filters Notion accepts: a
+// top-level and/or may contain and/or groups, but those may not nest further.
+const MaxFilterDepth = 2
+
+// Validate checks that every node of the filter is exactly one of: a
+// condition on a property or timestamp, an and group or an or group, and that
+// groups are not empty and nest at most MaxFilterDepth levels.
+func (f *Filter) Validate() error {
+	return f.validate(0)
+}
+
+func (f *Filter) validate(depth int) error {
+	if f == nil {
+		return errors.New("filter is nil")
+	}
+
+	conditions := 0
+	for _, set := range []bool{
+		f.Title != nil, f.RichText != nil, f.Select != nil, f.Status != nil,
+		f.MultiSelect != nil, f.Checkbox != nil, f.Date != nil,
+		f.CreatedTime != nil, f.LastEditedTime != nil,
+	} {
+		if set {
+			conditions++
+		}
+	}
+
+	kinds := 0
+	if conditions > 0 || f.Property != "" || f.Timestamp != "" {
+		kinds++
+	}
+	if f.And != nil {
+		kinds++
+	}
+	if f.Or != nil {
+		kinds++
+	}
+	switch {
+	case kinds == 0:
+		return errors.New("filter has no condition, and or or")
+	case kinds > 1:
+		return errors.New("filter must be only one of a condition, an and or an or")
+	}
+
+	group, op := f.And, "and"
+	if f.Or != nil {
+		group, op = f.Or, "or"
+	}
+	if group == nil {
+		return f.validateCondition(conditions)
+	}
+	if len(group) == 0 {
+		return fmt.Errorf("%s filter is empty", op)
+	}
+	if depth == MaxFilterDepth {
+		return fmt.Errorf("compound filters nest more than %d levels deep", MaxFilterDepth)
+	}
+	for i, sub := range group {
+		if err := sub.validate(depth + 1); err != nil {
+			return fmt.Errorf("%s[%d]: %w", op, i, err)
+		}
+	}
+	return nil
+}
+
+// validateCondition checks a leaf filter holding the given number of conditions
+func (f *Filter) validateCondition(conditions int) error {
+	if conditions != 1 {
+		return fmt.Errorf("filter on %q must have exactly one condition, has %d", f.Property+f.Timestamp, conditions)
+	}
+	timestamp := f.CreatedTime != nil || f.LastEditedTime != nil
+	switch {
+	case f.Property != "" && f.Timestamp != "":
+		return errors.New("filter has both a property and a timestamp")
+	case timestamp && f.Timestamp == "":
+		return errors.New("timestamp condition without a timestamp")
+	case !timestamp && f.Property == "":
+		return errors.New("property condition without a property")
+	case f.Title != nil && *f.Title == TextCondition{}, f.RichText != nil && *f.RichText == TextCondition{}:
+		return fmt.Errorf("text condition on %q is empty; use IsEmpty to match empty text", f.Property)
+	}
+	return nil
+}
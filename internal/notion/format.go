@@ -0,0 +1,170 @@
+package notion
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// currencySymbols maps Notion number formats to the symbol printed before the amount
+var currencySymbols = map[string]string{
+	"dollar":            "$",
+	"canadian_dollar":   "CA$",
+	"australian_dollar": "A$",
+	"euro":              "€",
+	"pound":             "£",
+	"yen":               "¥",
+	"ruble":             "₽",
+	"rupee":             "₹",
+	"won":               "₩",
+	"yuan":              "CN¥",
+	"real":              "R$",
+	"lira":              "₺",
+	"franc":             "CHF ",
+	"peso":              "$",
+}
+
+// zeroDecimalCurrencies are currencies rendered without fractional digits
+var zeroDecimalCurrencies = map[string]bool{
+	"yen": true,
+	"won": true,
+}
+
+// FormatNumber renders f the way Notion displays a number property with the
+// given format ("number", "number_with_commas", "percent" or a currency such as
+// "dollar"). Unknown formats fall back to the raw value.
+func FormatNumber(f float64, format string) string {
+	switch format {
+	case "number_with_commas":
+		return groupThousands(strconv.FormatFloat(f, 'f', -1, 64))
+	case "percent":
+		// Notion stores percentages as fractions: 0.25 is shown as 25%.
+		return strconv.FormatFloat(f*100, 'f', -1, 64) + "%"
+	}
+
+	if sym, ok := currencySymbols[format]; ok {
+		prec := 2
+		if zeroDecimalCurrencies[format] {
+			prec = 0
+		}
+		sign := ""
+		if f < 0 {
+			sign = "-"
+		}
+		return sign + sym + groupThousands(strconv.FormatFloat(math.Abs(f), 'f', prec, 64))
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// groupThousands inserts commas into the integer part of a formatted number
+func groupThousands(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	if hasFrac {
+		return sign + groupDigits(intPart, ",") + "." + frac
+	}
+	return sign + groupDigits(intPart, ",")
+}
+
+// groupDigits inserts sep between every group of three digits, from the right
+func groupDigits(digits, sep string) string {
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(sep)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ExtractOptions controls how ExtractStringsWith renders values. The zero
+// value renders them exactly like ExtractStrings.
+type ExtractOptions struct {
+	Number NumberOptions
+
+	// Location, when set, converts timestamps of date, formula date, rollup
+	// date, created_time and last_edited_time values to that time zone, so
+	// values stored with different offsets read alike
+	Location *time.Location
+	// DateLayout, when set, formats those timestamps with the given time
+	// layout instead of echoing the API's RFC 3339 string
+	DateLayout string
+}
+
+// FormatTime renders a timestamp from the API per the date options. When
+// neither is set, and for date-only values such as "2024-01-02" or strings
+// that don't parse, s is returned unchanged.
+func (o ExtractOptions) FormatTime(s string) string {
+	if o.Location == nil && o.DateLayout == "" {
+		return s
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		// Date-only values have no time of day or zone to convert.
+		return s
+	}
+	if o.Location != nil {
+		t = t.In(o.Location)
+	}
+	layout := o.DateLayout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return t.Format(layout)
+}
+
+// NumberOptions controls how number, formula number and rollup number values
+// are rendered. The zero value gives the shortest exact form without
+// grouping, e.g. 1000000 or 0.125.
+//
+// For example {Fixed: true, Precision: 2, ThousandsSep: ",", Prefix: "$"}
+// renders -1234.5 as -$1,234.50, and {ThousandsSep: ".", DecimalSep: ","}
+// renders it as -1.234,5.
+type NumberOptions struct {
+	// Fixed rounds to exactly Precision decimals
+	Fixed     bool
+	Precision int
+	// ThousandsSep separates groups of three integer digits; empty means no grouping
+	ThousandsSep string
+	// DecimalSep separates the decimals; empty means "."
+	DecimalSep string
+	// Prefix and Suffix are put around the number, after any minus sign, e.g. "$" or " %"
+	Prefix, Suffix string
+}
+
+func (o NumberOptions) format(f float64) string {
+	if o == (NumberOptions{}) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	prec := -1
+	if o.Fixed {
+		prec = o.Precision
+	}
+	s := strconv.FormatFloat(math.Abs(f), 'f', prec, 64)
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	if o.ThousandsSep != "" {
+		intPart = groupDigits(intPart, o.ThousandsSep)
+	}
+
+	var b strings.Builder
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	b.WriteString(o.Prefix)
+	b.WriteString(intPart)
+	if hasFrac {
+		if o.DecimalSep == "" {
+			b.WriteByte('.')
+		} else {
+			b.WriteString(o.DecimalSep)
+		}
+		b.WriteString(frac)
+	}
+	b.WriteString(o.Suffix)
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package notion
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+// dateAndFormula returns the properties of a page with the date start–end
+// both in a date column and in a formula returning the same date
+func dateAndFormula(t *testing.T, start, end string) map[string]PropertyValue {
+	t.Helper()
+	date := map[string]any{"start": start, "end": nil}
+	if end != "" {
+		date["end"] = end
+	}
+	b, err := json.Marshal(map[string]any{
+		"Date":    map[string]any{"type": "date", "date": date},
+		"Formula": map[string]any{"type": "formula", "formula": map[string]any{"type": "date", "date": date}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var props map[string]PropertyValue
+	if err := json.Unmarshal(b, &props); err != nil {
+		t.Fatal(err)
+	}
+	return props
+}
+
+func TestFormulaDateRendersLikeDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		start string
+		end   string
+		loc   string // -timezone; empty leaves timestamps as returned
+		want  []string
+	}{
+		{"date only", "2024-03-01", "", "Europe/Berlin", []string{"2024-03-01"}},
+		{"timestamp unconverted", "2024-03-01T10:00:00.000+00:00", "", "", []string{"2024-03-01T10:00:00.000+00:00"}},
+		{"timestamp to zone", "2024-03-01T10:00:00.000+00:00", "", "Europe/Berlin", []string{"2024-03-01T11:00:00+01:00"}},
+		{"range to zone", "2024-07-01T08:00:00Z", "2024-07-01T09:30:00+02:00", "UTC", []string{"2024-07-01T08:00:00Z → 2024-07-01T07:30:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props := dateAndFormula(t, tt.start, tt.end)
+			var opts ExtractOptions
+			if tt.loc != "" {
+				loc, err := time.LoadLocation(tt.loc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				opts.Location = loc
+			}
+
+			got := ExtractStringsWith(props["Date"], opts)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("date = %q, want %q", got, tt.want)
+			}
+			if formula := ExtractStringsWith(props["Formula"], opts); !slices.Equal(formula, got) {
+				t.Errorf("formula = %q, want %q like the date", formula, got)
+			}
+		})
+	}
+}
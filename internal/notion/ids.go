@@ -0,0 +1,21 @@
+package notion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeID validates a Notion object ID, a UUID given with or without
+// dashes, and returns it in the lowercase dashed 8-4-4-4-12 form
+func NormalizeID(id string) (string, error) {
+	hex := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(id), "-", ""))
+	if len(hex) != 32 {
+		return "", fmt.Errorf("invalid Notion ID %q: want 32 hex digits", id)
+	}
+	for _, r := range hex {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return "", fmt.Errorf("invalid Notion ID %q: want 32 hex digits", id)
+		}
+	}
+	return hex[:8] + "-" + hex[8:12] + "-" + hex[12:16] + "-" + hex[16:20] + "-" + hex[20:], nil
+}
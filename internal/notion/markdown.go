@@ -0,0 +1,102 @@
+package notion
+
+import (
+	"strings"
+)
+
+// markdownEscaper escapes characters that Markdown would treat as formatting
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`~`, `\~`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+)
+
+// ExtractMarkdown renders a title or rich_text property as Markdown,
+// preserving bold, italic, strikethrough, underline, inline code and links.
+// Other property types yield "".
+func ExtractMarkdown(p PropertyValue) string {
+	return RichTextToMarkdown(ExtractRichText(p))
+}
+
+// RichTextToMarkdown renders rich text segments as Markdown. Underline has no
+// Markdown syntax and is written as <u>…</u>; colors are dropped.
+func RichTextToMarkdown(rts []RichText) string {
+	var b strings.Builder
+	for _, rt := range rts {
+		b.WriteString(segmentToMarkdown(rt))
+	}
+	return b.String()
+}
+
+func segmentToMarkdown(rt RichText) string {
+	text := rt.PlainText
+	if rt.Text != nil {
+		text = rt.Text.Content
+	}
+	if text == "" {
+		return ""
+	}
+
+	// Emphasis markers must hug the text, so surrounding whitespace stays outside them.
+	core := strings.TrimSpace(text)
+	if core == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, core)]
+	trail := text[len(lead)+len(core):]
+
+	a := rt.Annotations
+	if a == nil {
+		a = &Annotations{}
+	}
+
+	if a.Code {
+		core = codeSpan(core)
+	} else {
+		core = markdownEscaper.Replace(core)
+	}
+	if a.Strikethrough {
+		core = "~~" + core + "~~"
+	}
+	if a.Italic {
+		core = "_" + core + "_"
+	}
+	if a.Bold {
+		core = "**" + core + "**"
+	}
+	if a.Underline {
+		core = "<u>" + core + "</u>"
+	}
+	if href := segmentHref(rt); href != "" {
+		core = "[" + core + "](" + href + ")"
+	}
+	return lead + core + trail
+}
+
+// segmentHref returns the link target of a segment, if any
+func segmentHref(rt RichText) string {
+	if rt.Href != nil && *rt.Href != "" {
+		return *rt.Href
+	}
+	if rt.Text != nil && rt.Text.Link != nil {
+		return rt.Text.Link.URL
+	}
+	return ""
+}
+
+// codeSpan wraps s in enough backticks that backticks inside it survive
+func codeSpan(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
@@ -0,0 +1,54 @@
+package notion
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResponseMeta describes the HTTP response of an API call
+type ResponseMeta struct {
+	StatusCode int
+	RateLimit  RateLimit
+	// RetryAfter is the delay requested by the Retry-After header, if any
+	RetryAfter time.Duration
+	// RequestID is the X-Request-Id header Notion support asks for, if sent
+	RequestID string
+}
+
+// RateLimit holds the X-RateLimit-* response headers.
+// Limit and Remaining are -1 when the header was not sent.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	// Reset is the raw X-RateLimit-Reset value
+	Reset string
+}
+
+// response is a completed HTTP exchange
+type response struct {
+	body []byte
+	meta ResponseMeta
+}
+
+func parseResponseMeta(resp *http.Response) ResponseMeta {
+	return ResponseMeta{
+		StatusCode: resp.StatusCode,
+		RateLimit: RateLimit{
+			Limit:     headerInt(resp.Header, "X-RateLimit-Limit"),
+			Remaining: headerInt(resp.Header, "X-RateLimit-Remaining"),
+			Reset:     strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")),
+		},
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		RequestID:  strings.TrimSpace(resp.Header.Get("X-Request-Id")),
+	}
+}
+
+func headerInt(h http.Header, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(h.Get(name)))
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -2,8 +2,10 @@ package notion
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,45 +22,154 @@ const (
 	NotionVersion = "2025-09-03"
 	// DefaultPageSize is the default page size for queries
 	DefaultPageSize = 100
+	// MaxPageSize is the largest page size Notion accepts
+	MaxPageSize = 100
 	// HTTPTimeout is the timeout for HTTP requests
 	HTTPTimeout = 30 * time.Second
 )
 
 // Client represents a Notion API client
 type Client struct {
-	token string
-	http  *http.Client
+	token   string
+	http    *http.Client
+	timeout time.Duration
+	baseURL string
+	version string
+
+	maxAttempts   int
+	lookupTimeout time.Duration
+
+	reads  flightGroup
+	cache  *readCache
+	titles titleCache
+	stats  requestStats
 }
 
-// NewClient creates a new Notion API client
-func NewClient(token string) *Client {
-	return &Client{
-		token: token,
-		http:  &http.Client{Timeout: HTTPTimeout},
+// NewClient creates a new Notion API client.
+// Without options it talks to BaseURL with NotionVersion and HTTPTimeout.
+func NewClient(token string, opts ...ClientOption) *Client {
+	c := &Client{
+		token:       token,
+		baseURL:     BaseURL,
+		version:     NotionVersion,
+		maxAttempts: DefaultMaxAttempts,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	switch {
+	case c.http == nil:
+		timeout := c.timeout
+		if timeout == 0 {
+			timeout = HTTPTimeout
+		}
+		c.http = &http.Client{Timeout: timeout}
+	case c.timeout != 0:
+		hc := *c.http
+		hc.Timeout = c.timeout
+		c.http = &hc
 	}
+	return c
 }
 
-// Do performs an HTTP request to the Notion API
+// Do performs an HTTP request to the Notion API.
+// Concurrent identical reads are collapsed into a single HTTP call whose result is shared.
 func (c *Client) Do(ctx context.Context, method, path string, q url.Values, body any, out any) error {
+	_, err := c.DoWithMeta(ctx, method, path, q, body, out)
+	return err
+}
+
+// DoWithMeta performs a request like Do and also returns metadata of the
+// response, such as the remaining rate limit quota. The metadata is returned
+// for API errors too; it is zero when no response was received.
+func (c *Client) DoWithMeta(ctx context.Context, method, path string, q url.Values, body any, out any) (ResponseMeta, error) {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return ResponseMeta{}, fmt.Errorf("marshal request: %w", err)
+		}
+	}
+
+	var (
+		resp response
+		err  error
+	)
+	if isRead(method, path) {
+		resp, err = c.read(ctx, method, path, q, b)
+	} else {
+		resp, err = c.sendWithRetry(ctx, method, path, q, b)
+		if c.cache != nil {
+			// Cleared once the write is done, so that reads racing with it
+			// can't leave pre-write responses behind; see readCache.
+			c.cache.clear()
+		}
+	}
+	if err != nil {
+		return resp.meta, err
+	}
+
+	if out == nil {
+		return resp.meta, nil
+	}
+	if err := json.Unmarshal(resp.body, out); err != nil {
+		return resp.meta, &DecodeError{Method: method, Path: path, Body: strings.TrimSpace(string(resp.body)), Err: err}
+	}
+	return resp.meta, nil
+}
+
+// read serves a read request from the cache, an identical in-flight request, or the API
+func (c *Client) read(ctx context.Context, method, path string, q url.Values, body []byte) (response, error) {
+	key := method + " " + c.url(path, q) + " " + string(body)
+	var gen uint64
+	if c.cache != nil {
+		if r, ok := c.cache.get(key); ok {
+			return r, nil
+		}
+		gen = c.cache.generation()
+	}
+	for {
+		// With a cache, a read issued after a write doesn't join a call
+		// started before it, whose response may predate the write.
+		flightKey := strconv.FormatUint(gen, 10) + " " + key
+		r, shared, err := c.reads.do(flightKey, func() (response, error) {
+			r, err := c.sendWithRetry(ctx, method, path, q, body)
+			if err == nil && c.cache != nil {
+				c.cache.put(key, r, gen)
+			}
+			return r, err
+		})
+		// A shared call runs under the first caller's context. When that one
+		// was cancelled or timed out, callers whose own context is fine try
+		// again rather than fail with someone else's cancellation.
+		if shared && ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			continue
+		}
+		return r, err
+	}
+}
+
+// send performs a single HTTP round-trip. Non-2xx responses yield an *APIError.
+func (c *Client) send(ctx context.Context, method, path string, q url.Values, body []byte) (response, error) {
 	u := c.url(path, q)
 
 	var r io.Reader
 	if body != nil {
-		b, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("marshal request: %w", err)
-		}
-		r = bytes.NewReader(b)
+		r = bytes.NewReader(body)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, u, r)
 	if err != nil {
-		return fmt.Errorf("new request: %w", err)
+		return response{}, fmt.Errorf("new request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Notion-Version", NotionVersion)
+	req.Header.Set("Notion-Version", c.version)
 	req.Header.Set("Accept", "application/json")
+	// Set explicitly so compression also applies with a custom transport that
+	// disables it; the body is then decompressed here rather than by net/http.
+	req.Header.Set("Accept-Encoding", "gzip")
 	req.Header.Set("User-Agent", "notion-tools/1.0")
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
@@ -66,50 +177,132 @@ func (c *Client) Do(ctx context.Context, method, path string, q url.Values, body
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return fmt.Errorf("http do: %w", err)
+		c.stats.record(method, 0)
+		return response{}, fmt.Errorf("http do: %w", err)
 	}
 	defer resp.Body.Close()
+	c.stats.record(method, resp.StatusCode)
 
-	respBody, _ := io.ReadAll(resp.Body)
+	respBody, err := readBody(resp)
+	meta := parseResponseMeta(resp)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("notion API %s %s failed: status=%d body=%s",
-			method, path, resp.StatusCode, strings.TrimSpace(string(respBody)))
+		// The status matters more than a body that failed to read.
+		return response{meta: meta}, newAPIError(method, path, meta, strings.TrimSpace(string(respBody)))
+	}
+	if err != nil {
+		return response{meta: meta}, fmt.Errorf("read response: %w", err)
 	}
+	return response{body: respBody, meta: meta}, nil
+}
 
-	if out == nil {
-		return nil
+// readBody reads a response body, decompressing it if the server gzipped it
+func readBody(resp *http.Response) ([]byte, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return io.ReadAll(resp.Body)
 	}
-	if err := json.Unmarshal(respBody, out); err != nil {
-		return fmt.Errorf("unmarshal response: %w (body=%s)", err, strings.TrimSpace(string(respBody)))
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
 	}
-	return nil
+	defer zr.Close()
+	return io.ReadAll(zr)
 }
 
-func (*Client) url(path string, q url.Values) string {
-	u := BaseURL + path
+func (c *Client) url(path string, q url.Values) string {
+	u := strings.TrimRight(c.baseURL, "/") + path
 	if len(q) > 0 {
 		u += "?" + q.Encode()
 	}
 	return u
 }
 
+// GetPage retrieves a single page by ID. When properties are given only those
+// are returned, like filter_properties on a query; names and IDs both work.
+func (c *Client) GetPage(ctx context.Context, pageID string, properties ...string) (*Page, error) {
+	var resp Page
+	if err := c.Do(ctx, http.MethodGet, "/pages/"+pageID, FilterProperties(properties...), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // UpdatePage updates a Notion page with the given properties
 func (c *Client) UpdatePage(ctx context.Context, pageID string, properties map[string]PropertyValue) error {
+	if err := checkPropertyValues(properties); err != nil {
+		return fmt.Errorf("update page %s: %w", pageID, err)
+	}
 	req := UpdatePageRequest{
 		Properties: properties,
 	}
 	return c.Do(ctx, http.MethodPatch, "/pages/"+pageID, nil, req, nil)
 }
 
+// ArchivePage moves a page to the trash and returns the updated page
+func (c *Client) ArchivePage(ctx context.Context, pageID string) (*Page, error) {
+	return c.setInTrash(ctx, pageID, true)
+}
+
+// RestorePage takes a page out of the trash and returns the updated page
+func (c *Client) RestorePage(ctx context.Context, pageID string) (*Page, error) {
+	return c.setInTrash(ctx, pageID, false)
+}
+
+func (c *Client) setInTrash(ctx context.Context, pageID string, inTrash bool) (*Page, error) {
+	req := TrashPageRequest{InTrash: inTrash}
+	var resp Page
+	if err := c.Do(ctx, http.MethodPatch, "/pages/"+pageID, nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdatePageByID updates a page with properties keyed by property ID instead
+// of name, so automation keeps working when columns are renamed. The page
+// update endpoint accepts either the name or the ID as the key for the
+// NotionVersion this client sends; IDs are URL-encoded strings such as "title"
+// or "%3AUPp" as returned in PropertyValue.ID and PropertySchema.ID.
+func (c *Client) UpdatePageByID(ctx context.Context, pageID string, props map[string]PropertyValue) error {
+	byID := make(map[string]PropertyValue, len(props))
+	for id, v := range props {
+		if id == "" {
+			return fmt.Errorf("update page %s: empty property ID", pageID)
+		}
+		// The key identifies the property, so the value must not carry a conflicting ID.
+		v.ID = ""
+		byID[id] = v
+	}
+	return c.UpdatePage(ctx, pageID, byID)
+}
+
 // CreatePage creates a new page in the specified datasource
 func (c *Client) CreatePage(ctx context.Context, datasourceID string, properties map[string]PropertyValue) (*Page, error) {
+	return c.CreatePageFull(ctx, datasourceID, properties, PageContent{})
+}
+
+// PageContent is the optional content of a new page besides its properties
+type PageContent struct {
+	Children []Block
+	Icon     *Icon
+	Cover    *Cover
+}
+
+// CreatePageFull creates a new page in the specified datasource with initial
+// content. At most MaxAppendChildren children can be sent with the page;
+// append the rest with AppendBlockChildren.
+func (c *Client) CreatePageFull(ctx context.Context, datasourceID string, properties map[string]PropertyValue, content PageContent) (*Page, error) {
+	if len(content.Children) > MaxAppendChildren {
+		return nil, fmt.Errorf("create page: %d children exceed the limit of %d", len(content.Children), MaxAppendChildren)
+	}
+	if err := checkPropertyValues(properties); err != nil {
+		return nil, fmt.Errorf("create page: %w", err)
+	}
 	req := CreatePageRequest{
-		Parent: Parent{
-			Type:         "data_source_id",
-			DatasourceID: datasourceID,
-		},
+		Parent:     c.parent(datasourceID),
 		Properties: properties,
+		Children:   content.Children,
+		Icon:       content.Icon,
+		Cover:      content.Cover,
 	}
 	var resp Page
 	err := c.Do(ctx, http.MethodPost, "/pages", nil, req, &resp)
@@ -123,42 +316,82 @@ func (c *Client) CreatePage(ctx context.Context, datasourceID string, properties
 type CreatePageRequest struct {
 	Parent     Parent                   `json:"parent"`
 	Properties map[string]PropertyValue `json:"properties"`
+	Children   []Block                  `json:"children,omitempty"`
+	Icon       *Icon                    `json:"icon,omitempty"`
+	Cover      *Cover                   `json:"cover,omitempty"`
+}
+
+// Icon is the icon of a page: an emoji or an external image
+type Icon struct {
+	Type     string        `json:"type"`
+	Emoji    string        `json:"emoji,omitempty"`
+	External *ExternalFile `json:"external,omitempty"`
+}
+
+// EmojiIcon builds an emoji page icon
+func EmojiIcon(emoji string) *Icon {
+	return &Icon{Type: "emoji", Emoji: emoji}
+}
+
+// ExternalIcon builds a page icon from an image URL
+func ExternalIcon(url string) *Icon {
+	return &Icon{Type: "external", External: &ExternalFile{URL: url}}
 }
 
-// QueryPages queries pages in a datasource with optional filters
-func (c *Client) QueryPages(ctx context.Context, datasourceID string, filter map[string]any) (*QueryResponse, error) {
+// Cover is the cover image of a page
+type Cover struct {
+	Type     string        `json:"type"`
+	External *ExternalFile `json:"external,omitempty"`
+}
+
+// ExternalCover builds a page cover from an image URL
+func ExternalCover(url string) *Cover {
+	return &Cover{Type: "external", External: &ExternalFile{URL: url}}
+}
+
+// QueryPages queries pages in a datasource with an optional filter
+func (c *Client) QueryPages(ctx context.Context, datasourceID string, filter *Filter) (*QueryResponse, error) {
 	req := QueryRequest{
 		PageSize: DefaultPageSize,
+		Filter:   filter,
 	}
-
-	// If filter is provided, add it to the request
-	if filter != nil {
-		// For now, we'll implement a simple filter structure
-		// This could be expanded to support more complex filters
-		req.Filter = filter
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
 
 	var resp QueryResponse
-	err := c.Do(ctx, http.MethodPost, "/data_sources/"+datasourceID+"/query", nil, req, &resp)
+	err := c.Do(ctx, http.MethodPost, c.QueryPath(datasourceID), nil, req, &resp)
 	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// FindPageByTitle finds a page by title in a datasource
-func (c *Client) FindPageByTitle(ctx context.Context, datasourceID, title string) (*Page, error) {
-	filter := map[string]any{
-		"property": "Name",
-		"title": map[string]any{
-			"equals": title,
-		},
+// FindPageByTitle finds a page by title in a datasource, or returns nil when
+// there is none. The match is done by Notion with an equals filter on the
+// titleProperty title property, so it is exact and case-sensitive and only one page is fetched.
+// When several pages have the title, the oldest one (by created_time) is
+// returned. The query is retried like any other read, and bounded by
+// WithLookupTimeout when set.
+func (c *Client) FindPageByTitle(ctx context.Context, datasourceID, titleProperty, title string) (*Page, error) {
+	if c.lookupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.lookupTimeout)
+		defer cancel()
 	}
 
-	resp, err := c.QueryPages(ctx, datasourceID, filter)
-	if err != nil {
+	req := QueryRequest{
+		PageSize: 1,
+		Filter:   TitleEquals(titleProperty, title),
+		Sorts:    []Sort{TimestampSort("created_time", Ascending)},
+	}
+	if err := req.Validate(); err != nil {
 		return nil, err
 	}
+	var resp QueryResponse
+	if err := c.Do(ctx, http.MethodPost, c.QueryPath(datasourceID), nil, req, &resp); err != nil {
+		return nil, fmt.Errorf("find page titled %q: %w", title, err)
+	}
 
 	if len(resp.Results) > 0 {
 		return &resp.Results[0], nil
@@ -167,10 +400,56 @@ func (c *Client) FindPageByTitle(ctx context.Context, datasourceID, title string
 	return nil, nil // Not found
 }
 
+// FindPageByTitleFold finds a page whose titleProperty matches title after
+// normalization, so "alice " finds "Alice". Both sides are normalized by
+// trimming leading and trailing whitespace, collapsing inner runs of
+// whitespace to a single space, and comparing with Unicode case folding
+// (strings.EqualFold). The fetch is narrowed server-side with a title
+// "contains" filter on the first word. When several pages normalize equal,
+// the oldest one (by created_time) is returned.
+func (c *Client) FindPageByTitleFold(ctx context.Context, datasourceID, titleProperty, title string) (*Page, error) {
+	want := NormalizeTitle(title)
+	if want == "" {
+		return nil, nil
+	}
+	firstWord, _, _ := strings.Cut(want, " ")
+
+	req := QueryRequest{
+		Filter: &Filter{Property: titleProperty, Title: &TextCondition{Contains: firstWord}},
+		Sorts:  []Sort{TimestampSort("created_time", Ascending)},
+	}
+	var found *Page
+	err := c.QueryEach(ctx, datasourceID, req, nil, func(pg Page) error {
+		if found == nil && strings.EqualFold(NormalizeTitle(ExtractString(pg.Properties[titleProperty])), want) {
+			found = &pg
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// NormalizeTitle trims s and collapses inner whitespace runs to single spaces
+func NormalizeTitle(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
 // Parent represents the parent of a page
 type Parent struct {
 	Type         string `json:"type"`
 	DatasourceID string `json:"data_source_id,omitempty"`
+	DatabaseID   string `json:"database_id,omitempty"`
+}
+
+// parent returns the parent of pages created in datasourceID, a database on a
+// Legacy client
+func (c *Client) parent(datasourceID string) Parent {
+	if c.Legacy() {
+		return Parent{Type: "database_id", DatabaseID: datasourceID}
+	}
+	return Parent{Type: "data_source_id", DatasourceID: datasourceID}
 }
 
 // UpdatePageRequest represents a page update request
@@ -178,11 +457,114 @@ type UpdatePageRequest struct {
 	Properties map[string]PropertyValue `json:"properties"`
 }
 
+// TrashPageRequest moves a page to or out of the trash. in_trash replaces the
+// deprecated archived flag in the NotionVersion this client sends.
+type TrashPageRequest struct {
+	InTrash bool `json:"in_trash"`
+}
+
 // QueryRequest represents a query request
 type QueryRequest struct {
-	PageSize    int         `json:"page_size,omitempty"`
-	StartCursor *string     `json:"start_cursor,omitempty"`
-	Filter      interface{} `json:"filter,omitempty"`
+	PageSize    int     `json:"page_size,omitempty"`
+	StartCursor *string `json:"start_cursor,omitempty"`
+	Filter      *Filter `json:"filter,omitempty"`
+	Sorts       []Sort  `json:"sorts,omitempty"`
+
+	// Archived selects active pages (the default), trashed pages or both.
+	// It is applied alongside Filter: Filter narrows the pages it selects.
+	// Only QueryAll, QueryEach and QueryBatches honor it.
+	Archived ArchivedMode `json:"-"`
+}
+
+// ArchivedMode selects pages by whether they are in the trash
+type ArchivedMode string
+
+// Archived modes of a query
+const (
+	ArchivedExclude ArchivedMode = ""        // active pages only
+	ArchivedInclude ArchivedMode = "include" // active and trashed pages
+	ArchivedOnly    ArchivedMode = "only"    // trashed pages only
+)
+
+// trashed reports whether the page is in the trash, under either flag name
+func (p Page) trashed() bool {
+	return p.InTrash || p.Archived
+}
+
+// keeps reports whether a page belongs in the results of the mode
+func (m ArchivedMode) keeps(p Page) bool {
+	switch m {
+	case ArchivedInclude:
+		return true
+	case ArchivedOnly:
+		return p.trashed()
+	default:
+		return !p.trashed()
+	}
+}
+
+// Sort directions
+const (
+	Ascending  = "ascending"
+	Descending = "descending"
+)
+
+// Sort orders query results by a property or by a page timestamp
+type Sort struct {
+	Property  string `json:"property,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Direction string `json:"direction"`
+}
+
+// PropertySort orders by the value of a property
+func PropertySort(property, direction string) Sort {
+	return Sort{Property: property, Direction: direction}
+}
+
+// TimestampSort orders by a page timestamp: "created_time" or "last_edited_time"
+func TimestampSort(timestamp, direction string) Sort {
+	return Sort{Timestamp: timestamp, Direction: direction}
+}
+
+// Validate checks that the sort targets exactly one property or timestamp
+// and uses a known direction
+func (s Sort) Validate() error {
+	if s.Direction != Ascending && s.Direction != Descending {
+		return fmt.Errorf("invalid sort direction %q: expected %q or %q", s.Direction, Ascending, Descending)
+	}
+	switch {
+	case s.Property != "" && s.Timestamp != "":
+		return errors.New("sort sets both property and timestamp")
+	case s.Property == "" && s.Timestamp == "":
+		return errors.New("sort needs a property or a timestamp")
+	case s.Timestamp != "" && s.Timestamp != "created_time" && s.Timestamp != "last_edited_time":
+		return fmt.Errorf("invalid sort timestamp %q: expected created_time or last_edited_time", s.Timestamp)
+	}
+	return nil
+}
+
+// Validate checks the request locally before it is sent. A PageSize of 0
+// means DefaultPageSize.
+func (r QueryRequest) Validate() error {
+	switch r.Archived {
+	case ArchivedExclude, ArchivedInclude, ArchivedOnly:
+	default:
+		return fmt.Errorf("invalid archived mode %q: expected %q, %q or empty", r.Archived, ArchivedInclude, ArchivedOnly)
+	}
+	if r.PageSize < 0 || r.PageSize > MaxPageSize {
+		return fmt.Errorf("invalid page size %d: must be between 1 and %d, or 0 for the default", r.PageSize, MaxPageSize)
+	}
+	if r.Filter != nil {
+		if err := r.Filter.Validate(); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+	for i, s := range r.Sorts {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("sorts[%d]: %w", i, err)
+		}
+	}
+	return nil
 }
 
 // QueryResponse represents a query response
@@ -193,16 +575,82 @@ type QueryResponse struct {
 	NextCursor *string `json:"next_cursor"`
 }
 
+// More reports whether another page of results can be fetched
+func (r QueryResponse) More() bool {
+	return hasNextCursor(r.HasMore, r.NextCursor)
+}
+
+// Cursor returns the start cursor for the next page of results, or nil when there is none
+func (r QueryResponse) Cursor() *string {
+	if !r.More() {
+		return nil
+	}
+	return r.NextCursor
+}
+
+// RawQueryResponse is a query response whose results are kept as the
+// unmodified JSON returned by the API
+type RawQueryResponse struct {
+	Object     string            `json:"object"`
+	Results    []json.RawMessage `json:"results"`
+	HasMore    bool              `json:"has_more"`
+	NextCursor *string           `json:"next_cursor"`
+}
+
+// More reports whether another page of results can be fetched
+func (r RawQueryResponse) More() bool {
+	return hasNextCursor(r.HasMore, r.NextCursor)
+}
+
+// Cursor returns the start cursor for the next page of results, or nil when there is none
+func (r RawQueryResponse) Cursor() *string {
+	if !r.More() {
+		return nil
+	}
+	return r.NextCursor
+}
+
+func hasNextCursor(hasMore bool, cursor *string) bool {
+	return hasMore && cursor != nil && *cursor != ""
+}
+
+// ErrMissingCursor reports a malformed response that claims more results but
+// carries no cursor to fetch them with
+var ErrMissingCursor = errors.New("HasMore is true but cursor is empty")
+
+// CheckCursor returns ErrMissingCursor when HasMore is set without a usable
+// cursor, so callers don't silently stop short of the full result set
+func (r QueryResponse) CheckCursor() error {
+	return checkCursor(r.HasMore, r.NextCursor)
+}
+
+// CheckCursor returns ErrMissingCursor when HasMore is set without a usable cursor
+func (r RawQueryResponse) CheckCursor() error {
+	return checkCursor(r.HasMore, r.NextCursor)
+}
+
+func checkCursor(hasMore bool, cursor *string) error {
+	if hasMore && !hasNextCursor(hasMore, cursor) {
+		return ErrMissingCursor
+	}
+	return nil
+}
+
 // Page represents a Notion page
 type Page struct {
-	Object     string                   `json:"object"`
-	ID         string                   `json:"id"`
-	Properties map[string]PropertyValue `json:"properties"`
+	Object         string                   `json:"object"`
+	ID             string                   `json:"id"`
+	URL            string                   `json:"url,omitempty"`
+	CreatedTime    string                   `json:"created_time,omitempty"`
+	LastEditedTime string                   `json:"last_edited_time,omitempty"`
+	InTrash        bool                     `json:"in_trash,omitempty"`
+	Archived       bool                     `json:"archived,omitempty"`
+	Properties     map[string]PropertyValue `json:"properties"`
 }
 
 // PropertyValue represents a property value
 type PropertyValue struct {
-	ID   string `json:"id"`
+	ID   string `json:"id,omitempty"`
 	Type string `json:"type"`
 
 	Title       []RichText     `json:"title,omitempty"`
@@ -220,28 +668,89 @@ type PropertyValue struct {
 	Relation    []RelationRef  `json:"relation,omitempty"`
 	Formula     *FormulaValue  `json:"formula,omitempty"`
 	Rollup      *RollupValue   `json:"rollup,omitempty"`
+	Files       []FileValue    `json:"files,omitempty"`
+
+	CreatedTime    *string `json:"created_time,omitempty"`
+	LastEditedTime *string `json:"last_edited_time,omitempty"`
+	CreatedBy      *User   `json:"created_by,omitempty"`
+	LastEditedBy   *User   `json:"last_edited_by,omitempty"`
+
+	UniqueID *UniqueIDValue `json:"unique_id,omitempty"`
+
+	// HasMore is set on relation values that list only the first of their
+	// pages. ExtractStrings then returns just those; CompleteProperty or
+	// GetFullProperty retrieves the rest.
+	HasMore bool `json:"has_more,omitempty"`
 }
 
 // RichText represents rich text
 type RichText struct {
-	Type string       `json:"type"`
-	Text *TextContent `json:"text,omitempty"`
+	Type        string       `json:"type"`
+	Text        *TextContent `json:"text,omitempty"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+	PlainText   string       `json:"plain_text,omitempty"`
+	Href        *string      `json:"href,omitempty"`
 }
 
 // TextContent represents the text content of rich text
 type TextContent struct {
 	Content string `json:"content"`
+	Link    *Link  `json:"link,omitempty"`
+}
+
+// Link is the target of linked text
+type Link struct {
+	URL string `json:"url"`
+}
+
+// Annotations represents the formatting of rich text
+type Annotations struct {
+	Bold          bool   `json:"bold"`
+	Italic        bool   `json:"italic"`
+	Strikethrough bool   `json:"strikethrough"`
+	Underline     bool   `json:"underline"`
+	Code          bool   `json:"code"`
+	Color         string `json:"color,omitempty"`
 }
 
 // SelectOption represents a select option
 type SelectOption struct {
+	ID   string `json:"id,omitempty"`
 	Name string `json:"name"`
+	// Color is Notion's color name, e.g. "green" or "default"
+	Color string `json:"color,omitempty"`
+}
+
+// SelectedOptions returns the options chosen in a select, status or
+// multi_select property with their IDs and colors, or nil for other types.
+// ExtractStrings returns just their names.
+func SelectedOptions(p PropertyValue) []SelectOption {
+	switch p.Type {
+	case "select":
+		if p.Select != nil {
+			return []SelectOption{*p.Select}
+		}
+	case "status":
+		if p.Status != nil {
+			return []SelectOption{*p.Status}
+		}
+	case "multi_select":
+		return p.MultiSelect
+	}
+	return nil
 }
 
 // User represents a user
 type User struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID     string      `json:"id"`
+	Name   string      `json:"name,omitempty"`
+	Type   string      `json:"type,omitempty"`
+	Person *PersonInfo `json:"person,omitempty"`
+}
+
+// PersonInfo holds the details of a user of type "person"
+type PersonInfo struct {
+	Email string `json:"email,omitempty"`
 }
 
 // DateValue represents a date value
@@ -264,15 +773,64 @@ type FormulaValue struct {
 	Date    *DateValue `json:"date,omitempty"`
 }
 
+// FileValue represents an entry of a files property: a file uploaded to
+// Notion ("file") or a link to an external one ("external")
+type FileValue struct {
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	File     *HostedFile   `json:"file,omitempty"`
+	External *ExternalFile `json:"external,omitempty"`
+}
+
+// HostedFile is a file uploaded to Notion. Its URL is signed and expires
+// (see ExpiryTime), typically after an hour, so it must be used promptly
+// rather than stored.
+type HostedFile struct {
+	URL        string `json:"url"`
+	ExpiryTime string `json:"expiry_time,omitempty"`
+}
+
+// ExternalFile is a file hosted outside Notion
+type ExternalFile struct {
+	URL string `json:"url"`
+}
+
+// URL returns the download URL of the file: the signed Notion URL for
+// uploaded files or the external URL otherwise
+func (f FileValue) URL() string {
+	switch {
+	case f.Type == "file" && f.File != nil:
+		return f.File.URL
+	case f.Type == "external" && f.External != nil:
+		return f.External.URL
+	default:
+		return ""
+	}
+}
+
+// UniqueIDValue represents an auto-incrementing unique_id, e.g. TASK-42
+type UniqueIDValue struct {
+	Number int64   `json:"number"`
+	Prefix *string `json:"prefix"`
+}
+
 // RollupValue represents a rollup value
 type RollupValue struct {
-	Type   string          `json:"type"`
-	Number *float64        `json:"number,omitempty"`
-	Date   *DateValue      `json:"date,omitempty"`
-	Array  []PropertyValue `json:"array,omitempty"`
+	Type     string          `json:"type"`
+	Function string          `json:"function,omitempty"`
+	Number   *float64        `json:"number,omitempty"`
+	Date     *DateValue      `json:"date,omitempty"`
+	Array    []PropertyValue `json:"array,omitempty"`
 }
 
+// ExtractStrings returns the plain text values of a property, one per value
+// of multi-valued properties. It is ExtractStringsWith with default options.
 func ExtractStrings(p PropertyValue) []string {
+	return ExtractStringsWith(p, ExtractOptions{})
+}
+
+// ExtractStringsWith is ExtractStrings with control over how numbers and dates are rendered
+func ExtractStringsWith(p PropertyValue, opts ExtractOptions) []string {
 	switch p.Type {
 	case "title":
 		s := concatRichText(p.Title)
@@ -318,14 +876,30 @@ func ExtractStrings(p PropertyValue) []string {
 		}
 		out := make([]string, 0, len(p.People))
 		for _, u := range p.People {
-			if u.Name != "" {
-				out = append(out, u.Name)
-			} else if u.ID != "" {
-				out = append(out, u.ID)
+			if name := userName(u); name != "" {
+				out = append(out, name)
 			}
 		}
 		return out
 
+	case "created_by":
+		return userStrings(p.CreatedBy)
+
+	case "last_edited_by":
+		return userStrings(p.LastEditedBy)
+
+	case "created_time":
+		if p.CreatedTime == nil || *p.CreatedTime == "" {
+			return nil
+		}
+		return []string{opts.FormatTime(*p.CreatedTime)}
+
+	case "last_edited_time":
+		if p.LastEditedTime == nil || *p.LastEditedTime == "" {
+			return nil
+		}
+		return []string{opts.FormatTime(*p.LastEditedTime)}
+
 	case "email":
 		if p.Email == nil || *p.Email == "" {
 			return nil
@@ -348,7 +922,7 @@ func ExtractStrings(p PropertyValue) []string {
 		if p.Number == nil {
 			return nil
 		}
-		return []string{strconv.FormatFloat(*p.Number, 'f', -1, 64)}
+		return []string{opts.Number.format(*p.Number)}
 
 	case "checkbox":
 		if p.Checkbox == nil {
@@ -357,13 +931,7 @@ func ExtractStrings(p PropertyValue) []string {
 		return []string{strconv.FormatBool(*p.Checkbox)}
 
 	case "date":
-		if p.Date == nil || p.Date.Start == "" {
-			return nil
-		}
-		if p.Date.End != nil && *p.Date.End != "" {
-			return []string{p.Date.Start + " → " + *p.Date.End}
-		}
-		return []string{p.Date.Start}
+		return formatDate(p.Date, opts)
 
 	case "relation":
 		if len(p.Relation) == 0 {
@@ -377,6 +945,29 @@ func ExtractStrings(p PropertyValue) []string {
 		}
 		return out
 
+	case "files":
+		// Uploaded files have signed URLs that expire; use them promptly.
+		if len(p.Files) == 0 {
+			return nil
+		}
+		out := make([]string, 0, len(p.Files))
+		for _, f := range p.Files {
+			if u := f.URL(); u != "" {
+				out = append(out, u)
+			}
+		}
+		return out
+
+	case "unique_id":
+		if p.UniqueID == nil {
+			return nil
+		}
+		n := strconv.FormatInt(p.UniqueID.Number, 10)
+		if p.UniqueID.Prefix != nil && *p.UniqueID.Prefix != "" {
+			return []string{*p.UniqueID.Prefix + "-" + n}
+		}
+		return []string{n}
+
 	case "formula":
 		if p.Formula == nil {
 			return nil
@@ -391,20 +982,14 @@ func ExtractStrings(p PropertyValue) []string {
 			if p.Formula.Number == nil {
 				return nil
 			}
-			return []string{strconv.FormatFloat(*p.Formula.Number, 'f', -1, 64)}
+			return []string{opts.Number.format(*p.Formula.Number)}
 		case "boolean":
 			if p.Formula.Boolean == nil {
 				return nil
 			}
 			return []string{strconv.FormatBool(*p.Formula.Boolean)}
 		case "date":
-			if p.Formula.Date == nil || p.Formula.Date.Start == "" {
-				return nil
-			}
-			if p.Formula.Date.End != nil && *p.Formula.Date.End != "" {
-				return []string{p.Formula.Date.Start + " → " + *p.Formula.Date.End}
-			}
-			return []string{p.Formula.Date.Start}
+			return formatDate(p.Formula.Date, opts)
 		default:
 			return nil
 		}
@@ -418,19 +1003,19 @@ func ExtractStrings(p PropertyValue) []string {
 			if p.Rollup.Number == nil {
 				return nil
 			}
-			return []string{strconv.FormatFloat(*p.Rollup.Number, 'f', -1, 64)}
+			return []string{opts.Number.format(*p.Rollup.Number)}
 		case "date":
-			if p.Rollup.Date == nil || p.Rollup.Date.Start == "" {
-				return nil
-			}
-			if p.Rollup.Date.End != nil && *p.Rollup.Date.End != "" {
-				return []string{p.Rollup.Date.Start + " → " + *p.Rollup.Date.End}
-			}
-			return []string{p.Rollup.Date.Start}
+			return formatDate(p.Rollup.Date, opts)
 		case "array":
+			if f, ok := aggregateRollup(*p.Rollup); ok {
+				return []string{opts.Number.format(f)}
+			}
 			var out []string
 			for _, item := range p.Rollup.Array {
-				out = append(out, ExtractStrings(item)...)
+				out = append(out, ExtractStringsWith(item, opts)...)
+			}
+			if p.Rollup.Function == "show_unique" {
+				out = uniqueStrings(out)
 			}
 			return out
 		default:
@@ -442,6 +1027,37 @@ func ExtractStrings(p PropertyValue) []string {
 	}
 }
 
+// userName renders a user by name, falling back to the ID
+func userName(u User) string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.ID
+}
+
+func userStrings(u *User) []string {
+	if u == nil {
+		return nil
+	}
+	if name := userName(*u); name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// formatDate renders date, formula date and rollup date values the same way,
+// joining ranges with an arrow
+func formatDate(d *DateValue, opts ExtractOptions) []string {
+	if d == nil || d.Start == "" {
+		return nil
+	}
+	start := opts.FormatTime(d.Start)
+	if d.End != nil && *d.End != "" {
+		return []string{start + " → " + opts.FormatTime(*d.End)}
+	}
+	return []string{start}
+}
+
 func concatRichText(rts []RichText) string {
 	var b strings.Builder
 	for _, rt := range rts {
@@ -452,6 +1068,20 @@ func concatRichText(rts []RichText) string {
 	return strings.TrimSpace(b.String())
 }
 
+// ExtractString returns the first value ExtractStrings yields for p, trimmed,
+// or "" when there is none. For single-valued types that is the whole value:
+//   - title and rich_text: the text of all segments concatenated
+//   - select and status: the option name
+//   - number, checkbox, email, url, phone_number, unique_id: the value as text
+//   - date: the start, or "start → end" for a range
+//   - created_time, last_edited_time: the RFC 3339 timestamp
+//   - created_by, last_edited_by: the user's name, else its ID
+//   - formula and number or date rollups: their result, like the types above
+//
+// Multi-valued types collapse to their first value only: the first option of
+// a multi_select, the first user of people, the first related page ID of a
+// relation, the first file URL, the first item of an array rollup. Use
+// ExtractStringJoined to keep all of them.
 func ExtractString(p PropertyValue) string {
 	strs := ExtractStrings(p)
 	if len(strs) > 0 {
@@ -459,3 +1089,24 @@ func ExtractString(p PropertyValue) string {
 	}
 	return ""
 }
+
+// ExtractStringJoined returns every value ExtractStrings yields for p joined
+// with sep, or "" when there is none. Single-valued types give the same result
+// as ExtractString.
+func ExtractStringJoined(p PropertyValue, sep string) string {
+	return strings.Join(ExtractStrings(p), sep)
+}
+
+// ExtractRichText returns the raw rich text segments of a title or rich_text
+// property, preserving segment boundaries that ExtractStrings flattens.
+// Other property types yield nil.
+func ExtractRichText(p PropertyValue) []RichText {
+	switch p.Type {
+	case "title":
+		return p.Title
+	case "rich_text":
+		return p.RichText
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,92 @@
+package notion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// renamableColumns is a page whose properties can be renamed. Its update
+// endpoint accepts a property's name or ID as the key, like Notion's, and
+// rejects keys matching neither.
+type renamableColumns struct {
+	names   map[string]string // property ID to current name
+	values  map[string]PropertyValue
+	updates int
+}
+
+func (p *renamableColumns) rename(id, name string) { p.names[id] = name }
+
+func (p *renamableColumns) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch || r.URL.Path != "/pages/page-1" {
+		http.Error(w, "unexpected "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	p.updates++
+	var req UpdatePageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	for key, v := range req.Properties {
+		id, ok := p.resolve(key)
+		if !ok || v.ID != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"object":"error","status":400,"code":"validation_error","message":"` + key + ` is not a property that exists."}`))
+			return
+		}
+		p.values[id] = v
+	}
+	w.Write([]byte(`{"object":"page","id":"page-1"}`))
+}
+
+func (p *renamableColumns) resolve(key string) (string, bool) {
+	if _, ok := p.names[key]; ok {
+		return key, true
+	}
+	for id, name := range p.names {
+		if name == key {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func TestUpdatePageByID(t *testing.T) {
+	page := &renamableColumns{names: map[string]string{"title": "Name", "%3AUPp": "Score"}, values: map[string]PropertyValue{}}
+	srv := httptest.NewServer(page)
+	defer srv.Close()
+	client := NewClient("test-token", WithBaseURL(srv.URL), WithMaxAttempts(1))
+
+	if err := client.UpdatePageByID(t.Context(), "page-1", map[string]PropertyValue{"%3AUPp": NumberValue(1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// After the rename the old name no longer addresses the column, the ID still does.
+	page.rename("%3AUPp", "Points")
+	if err := client.UpdatePage(t.Context(), "page-1", map[string]PropertyValue{"Score": NumberValue(2)}); !HasStatus(err, http.StatusBadRequest) {
+		t.Errorf("update by the old name: err = %v, want a 400", err)
+	}
+	// A value read from another page carries its own ID, which must not be sent.
+	score := NumberValue(3)
+	score.ID = "other"
+	if err := client.UpdatePageByID(t.Context(), "page-1", map[string]PropertyValue{"%3AUPp": score, "title": TitleValue("Alice")}); err != nil {
+		t.Fatalf("update by ID after the rename: %v", err)
+	}
+	if got := page.values["%3AUPp"].Number; got == nil || *got != 3 {
+		t.Errorf("Points = %v, want 3", got)
+	}
+	if got := ExtractString(page.values["title"]); got != "Alice" {
+		t.Errorf("Name = %q, want Alice", got)
+	}
+
+	before := page.updates
+	if err := client.UpdatePageByID(t.Context(), "page-1", map[string]PropertyValue{"": NumberValue(4)}); err == nil {
+		t.Error("an empty property ID was accepted")
+	}
+	if page.updates != before {
+		t.Error("an update with an empty property ID was sent")
+	}
+}
@@ -0,0 +1,70 @@
+package notion
+
+import (
+	"net/http"
+	"time"
+)
+
+// ClientOption configures a Client created by NewClient
+type ClientOption func(*Client)
+
+// WithHTTPClient makes the client send requests through hc
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request (default HTTPTimeout).
+// A client passed to WithHTTPClient is copied rather than modified.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithLookupTimeout bounds each FindPageByTitle call, retries included, to d
+// on top of the caller's context, so one stuck lookup fails on its own instead
+// of using up the whole run's deadline. Zero means no extra bound.
+func WithLookupTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.lookupTimeout = d
+	}
+}
+
+// WithBaseURL points the client at another API root, e.g. an httptest server
+func WithBaseURL(u string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = u
+	}
+}
+
+// WithNotionVersion overrides the Notion-Version header (default NotionVersion)
+func WithNotionVersion(v string) ClientOption {
+	return func(c *Client) {
+		c.version = v
+	}
+}
+
+// WithMaxAttempts sets how often a request is tried in total when it fails
+// transiently as IsRetryable reports: rate limiting, unavailability, a
+// conflict_error on a write, or a network timeout or reset on a read
+// (default DefaultMaxAttempts).
+// Values below 1 disable retries.
+func WithMaxAttempts(n int) ClientOption {
+	return func(c *Client) {
+		if n < 1 {
+			n = 1
+		}
+		c.maxAttempts = n
+	}
+}
+
+// WithReadCache makes the client remember successful read responses
+// (GETs, queries and searches). Any write clears the cache so later reads
+// observe it. Errors are never cached.
+func WithReadCache() ClientOption {
+	return func(c *Client) {
+		c.cache = &readCache{}
+	}
+}
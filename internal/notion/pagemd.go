@@ -0,0 +1,217 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// blockNode is a block together with its nested children
+type blockNode struct {
+	Block
+	children []blockNode
+}
+
+// ExportPageMarkdown renders a page as a Markdown document: a front matter
+// block with the page's properties, its title as a heading and its body
+// blocks, nested blocks indented under their parents. Inline formatting is
+// rendered like RichTextToMarkdown; block types without a Markdown
+// equivalent are left out.
+func (c *Client) ExportPageMarkdown(ctx context.Context, pageID string) (string, error) {
+	pg, err := c.GetPage(ctx, pageID)
+	if err != nil {
+		return "", fmt.Errorf("export page %s: %w", pageID, err)
+	}
+	tree, err := c.blockTree(ctx, pageID)
+	if err != nil {
+		return "", fmt.Errorf("export page %s: %w", pageID, err)
+	}
+
+	var b strings.Builder
+	if err := writeFrontMatter(&b, pg); err != nil {
+		return "", fmt.Errorf("export page %s: %w", pageID, err)
+	}
+	for _, prop := range pg.Properties {
+		if prop.Type == "title" {
+			if title := ExtractMarkdown(prop); title != "" {
+				b.WriteString("\n# " + title + "\n")
+			}
+			break
+		}
+	}
+	if lines := markdownBlocks(tree, ""); len(lines) > 0 {
+		b.WriteString("\n" + strings.Join(lines, "\n") + "\n")
+	}
+	return b.String(), nil
+}
+
+// blockTree retrieves the children of a block, and theirs, recursively
+func (c *Client) blockTree(ctx context.Context, blockID string) ([]blockNode, error) {
+	blocks, err := c.GetAllBlockChildren(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]blockNode, len(blocks))
+	for i, blk := range blocks {
+		nodes[i].Block = blk
+		if blk.HasChildren {
+			if nodes[i].children, err = c.blockTree(ctx, blk.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// writeFrontMatter writes the page's properties, sorted by name, as YAML
+// front matter. Values are JSON-quoted, which YAML reads as strings and lists.
+func writeFrontMatter(b *strings.Builder, pg *Page) error {
+	names := make([]string, 0, len(pg.Properties))
+	for name := range pg.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString("---\n")
+	for _, name := range names {
+		values := ExtractStrings(pg.Properties[name])
+		var v any = values
+		switch len(values) {
+		case 0:
+			v = ""
+		case 1:
+			v = values[0]
+		}
+		k, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		enc, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.WriteString(string(k) + ": " + string(enc) + "\n")
+	}
+	b.WriteString("---\n")
+	return nil
+}
+
+// listKind returns "ol" or "ul" for blocks rendered as ordered or unordered
+// list items, which follow items of the same kind without blank lines, and ""
+// for other blocks
+func listKind(blockType string) string {
+	switch blockType {
+	case "numbered_list_item":
+		return "ol"
+	case "bulleted_list_item", "to_do", "toggle":
+		return "ul"
+	}
+	return ""
+}
+
+// markdownBlocks renders blocks as Markdown lines, each prefixed with indent.
+// Blank lines separate blocks except consecutive items of the same list.
+func markdownBlocks(nodes []blockNode, indent string) []string {
+	var (
+		lines    []string
+		num      int
+		prevList string
+	)
+	for _, n := range nodes {
+		if n.Type == "numbered_list_item" {
+			num++
+		} else {
+			num = 0
+		}
+
+		text := RichTextToMarkdown(n.RichText())
+		childIndent := indent + "  "
+		var block []string
+		switch n.Type {
+		case "paragraph":
+			block = prefixLines(text, indent, indent)
+		case "heading_1", "heading_2", "heading_3":
+			level := int(n.Type[len(n.Type)-1] - '0')
+			block = []string{indent + strings.Repeat("#", level) + " " + text}
+		case "bulleted_list_item", "toggle":
+			block = prefixLines(text, indent+"- ", childIndent)
+		case "numbered_list_item":
+			marker := fmt.Sprintf("%d. ", num)
+			childIndent = indent + strings.Repeat(" ", len(marker))
+			block = prefixLines(text, indent+marker, childIndent)
+		case "to_do":
+			box := "- [ ] "
+			if n.ToDo != nil && n.ToDo.Checked {
+				box = "- [x] "
+			}
+			block = prefixLines(text, indent+box, childIndent)
+		case "quote":
+			block = prefixLines(text, indent+"> ", indent+"> ")
+			childIndent = indent + "> "
+		case "code":
+			block = codeBlockLines(n.Code, indent)
+		default:
+			continue
+		}
+
+		list := listKind(n.Type)
+		if len(lines) > 0 && (list == "" || list != prevList) {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+		if children := markdownBlocks(n.children, childIndent); len(children) > 0 {
+			if list == "" {
+				lines = append(lines, strings.TrimRight(childIndent, " "))
+			}
+			lines = append(lines, children...)
+		}
+		prevList = list
+	}
+	return lines
+}
+
+// prefixLines splits multi-line text, prefixing the first line with first and the rest with rest
+func prefixLines(text, first, rest string) []string {
+	parts := strings.Split(text, "\n")
+	lines := make([]string, len(parts))
+	for i, p := range parts {
+		if i == 0 {
+			lines[i] = first + p
+		} else {
+			lines[i] = rest + p
+		}
+	}
+	return lines
+}
+
+// codeBlockLines renders a code block as a fenced block, its content unescaped
+func codeBlockLines(code *CodeBlock, indent string) []string {
+	if code == nil {
+		return nil
+	}
+	var b strings.Builder
+	for _, rt := range code.RichText {
+		if rt.Text != nil {
+			b.WriteString(rt.Text.Content)
+		} else {
+			b.WriteString(rt.PlainText)
+		}
+	}
+	content := b.String()
+
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	lang := code.Language
+	if lang == "plain text" {
+		lang = ""
+	}
+	lines := []string{indent + fence + lang}
+	for _, l := range strings.Split(content, "\n") {
+		lines = append(lines, indent+l)
+	}
+	return append(lines, indent+fence)
+}
@@ -0,0 +1,70 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ExportPageText renders a page as plain text for full-text indexing: its
+// title, then the text of every block, nested blocks after their parents.
+// Formatting, list markers and indentation are dropped. Blank lines separate
+// blocks, except consecutive list items, which take a line each.
+func (c *Client) ExportPageText(ctx context.Context, pageID string) (string, error) {
+	pg, err := c.GetPage(ctx, pageID)
+	if err != nil {
+		return "", fmt.Errorf("export page %s: %w", pageID, err)
+	}
+	tree, err := c.blockTree(ctx, pageID)
+	if err != nil {
+		return "", fmt.Errorf("export page %s: %w", pageID, err)
+	}
+
+	var lines []string
+	for _, prop := range pg.Properties {
+		if prop.Type == "title" {
+			if title := ExtractString(prop); title != "" {
+				lines = append(lines, title)
+			}
+			break
+		}
+	}
+	prevList := ""
+	textBlocks(tree, &lines, &prevList)
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// textBlocks appends the plain text of blocks and their children to lines.
+// prevList tracks the list kind of the last block written, as in
+// markdownBlocks, so items of one list stay on consecutive lines.
+func textBlocks(nodes []blockNode, lines *[]string, prevList *string) {
+	for _, n := range nodes {
+		if text := strings.TrimSpace(plainText(n.RichText())); text != "" {
+			list := listKind(n.Type)
+			if len(*lines) > 0 && (list == "" || list != *prevList) {
+				*lines = append(*lines, "")
+			}
+			*lines = append(*lines, strings.Split(text, "\n")...)
+			*prevList = list
+		}
+		textBlocks(n.children, lines, prevList)
+	}
+}
+
+// plainText concatenates rich text segments without any formatting,
+// including the text Notion renders for mentions and equations
+func plainText(rts []RichText) string {
+	var b strings.Builder
+	for _, rt := range rts {
+		switch {
+		case rt.PlainText != "":
+			b.WriteString(rt.PlainText)
+		case rt.Text != nil:
+			b.WriteString(rt.Text.Content)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,194 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// PeopleResolver resolves person names to pages of a people data source for
+// the lifetime of a run. Results are cached so each name costs at most one
+// lookup, and concurrent resolutions of the same name share a single lookup
+// and creation, so a process never creates the same person twice.
+type PeopleResolver struct {
+	client        PageAPI
+	dataSourceID  string
+	titleProperty string
+
+	// Icon, when set before the first Resolve, is given to every created page
+	Icon *Icon
+	// Properties, when set before the first Resolve, are set on every
+	// created page besides its title
+	Properties map[string]PropertyValue
+
+	mu        sync.Mutex
+	ids       map[string]string
+	pending   map[string]*pendingResolve
+	prewarmed bool
+}
+
+type pendingResolve struct {
+	done    chan struct{}
+	id      string
+	created bool
+	err     error
+}
+
+// NewPeopleResolver returns a resolver for the people data source whose
+// title property is titleProperty. Schema.TitleProperty finds it.
+func NewPeopleResolver(client PageAPI, dataSourceID, titleProperty string) *PeopleResolver {
+	return &PeopleResolver{
+		client:        client,
+		dataSourceID:  dataSourceID,
+		titleProperty: titleProperty,
+		ids:           map[string]string{},
+		pending:       map[string]*pendingResolve{},
+	}
+}
+
+// Prewarm lists the whole people data source once and caches every title.
+// Afterwards a cache miss means the person doesn't exist, so Resolve creates
+// it without a lookup. When titles repeat, the first page returned wins.
+func (r *PeopleResolver) Prewarm(ctx context.Context) error {
+	ids := map[string]string{}
+	err := r.client.QueryEach(ctx, r.dataSourceID, QueryRequest{}, nil, func(pg Page) error {
+		name := ExtractString(pg.Properties[r.titleProperty])
+		if _, dup := ids[name]; name != "" && !dup {
+			ids[name] = pg.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, id := range ids {
+		if _, ok := r.ids[name]; !ok {
+			r.ids[name] = id
+		}
+	}
+	r.prewarmed = true
+	return nil
+}
+
+// Resolve returns the page ID of the person called name, creating the page
+// on a true miss. created reports whether the page was created by this call.
+func (r *PeopleResolver) Resolve(ctx context.Context, name string) (pageID string, created bool, err error) {
+	return r.resolve(ctx, name, true)
+}
+
+// Lookup returns the page ID of the person called name without creating it.
+// An empty ID means no such page exists.
+func (r *PeopleResolver) Lookup(ctx context.Context, name string) (string, error) {
+	id, _, err := r.resolve(ctx, name, false)
+	return id, err
+}
+
+func (r *PeopleResolver) resolve(ctx context.Context, name string, create bool) (string, bool, error) {
+	r.mu.Lock()
+	if id, ok := r.ids[name]; ok {
+		r.mu.Unlock()
+		return id, false, nil
+	}
+	if p, ok := r.pending[name]; ok {
+		r.mu.Unlock()
+		<-p.done
+		if p.err != nil || p.id != "" || !create {
+			// Another caller resolved it; only the caller that created the page reports created.
+			return p.id, false, p.err
+		}
+		// The in-flight call was a lookup that found nothing; retry, possibly creating.
+		return r.resolve(ctx, name, create)
+	}
+	p := &pendingResolve{done: make(chan struct{})}
+	r.pending[name] = p
+	prewarmed := r.prewarmed
+	r.mu.Unlock()
+
+	p.id, p.created, p.err = r.fetch(ctx, name, create, prewarmed)
+
+	r.mu.Lock()
+	if p.err == nil && p.id != "" {
+		r.ids[name] = p.id
+	}
+	delete(r.pending, name)
+	r.mu.Unlock()
+	close(p.done)
+
+	return p.id, p.created, p.err
+}
+
+// fetch looks the person up (unless the cache was prewarmed) and creates the page when asked to
+func (r *PeopleResolver) fetch(ctx context.Context, name string, create, prewarmed bool) (string, bool, error) {
+	if create {
+		// The upsert looks the person up itself, and collapses a page
+		// created concurrently by another process.
+		pg, created, err := r.client.UpsertPersonByTitle(ctx, r.dataSourceID, r.titleProperty, name, r.Properties, PageContent{Icon: r.Icon})
+		if err != nil {
+			return "", false, err
+		}
+		return pg.ID, created, nil
+	}
+	if prewarmed {
+		return "", false, nil
+	}
+	existing, err := r.client.FindPageByTitle(ctx, r.dataSourceID, r.titleProperty, name)
+	if err != nil || existing == nil {
+		return "", false, err
+	}
+	return existing.ID, false, nil
+}
+
+// UpsertPersonByTitle returns the page whose titleProperty is name in the
+// people data source, creating it with props and content when there is none.
+// props must not set titleProperty. name is normalized with NormalizeTitle first.
+// created reports whether the returned page was created by this call.
+//
+// After creating, the title is looked up again: if another process created
+// the same person meanwhile, the oldest page is kept as the canonical one and
+// the page just created is moved to the trash, so concurrent upserts settle
+// on a single page. Pages created so close together that the query does not
+// list them yet can still slip through.
+func (c *Client) UpsertPersonByTitle(ctx context.Context, dataSourceID, titleProperty, name string, props map[string]PropertyValue, content PageContent) (pg *Page, created bool, err error) {
+	name = NormalizeTitle(name)
+	if name == "" {
+		return nil, false, errors.New("upsert person: empty name")
+	}
+	if _, ok := props[titleProperty]; ok {
+		return nil, false, fmt.Errorf("upsert person: properties must not set the title %q", titleProperty)
+	}
+
+	existing, err := c.FindPageByTitle(ctx, dataSourceID, titleProperty, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+
+	properties := maps.Clone(props)
+	if properties == nil {
+		properties = map[string]PropertyValue{}
+	}
+	properties[titleProperty] = TitleValue(name)
+	pg, err = c.CreatePageFull(ctx, dataSourceID, properties, content)
+	if err != nil {
+		return nil, false, fmt.Errorf("create person %q: %w", name, err)
+	}
+
+	canonical, err := c.FindPageByTitle(ctx, dataSourceID, titleProperty, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if canonical == nil || canonical.ID == pg.ID {
+		return pg, true, nil
+	}
+	if _, err := c.ArchivePage(ctx, pg.ID); err != nil {
+		return nil, false, fmt.Errorf("trash duplicate person %q page %s: %w", name, pg.ID, err)
+	}
+	return canonical, false, nil
+}
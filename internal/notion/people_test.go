@@ -0,0 +1,297 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// fakePeople is a people data source served over HTTP whose title property
+// is titleProperty. Title queries return the oldest page with that title, as
+// FindPageByTitle asks for, and trashed pages are left out. Like Notion, it
+// rejects filters and new pages naming another title property.
+type fakePeople struct {
+	*httptest.Server
+	titleProperty string
+
+	mu    sync.Mutex
+	pages []fakePerson
+
+	// beforeFind, when set, runs before each title query is answered
+	beforeFind func()
+}
+
+type fakePerson struct {
+	id      string
+	title   []RichText
+	trashed bool
+}
+
+func newFakePeople(t *testing.T, titleProperty string, existing ...string) *fakePeople {
+	t.Helper()
+	f := &fakePeople{titleProperty: titleProperty}
+	for i, name := range existing {
+		f.pages = append(f.pages, fakePerson{id: fmt.Sprintf("person-%d", i), title: textRichText(name)})
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/data_sources/people/query":
+			var req struct {
+				Filter *Filter `json:"filter"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Filter == nil || req.Filter.Title == nil {
+				t.Errorf("query is not a title query: %v", err)
+			}
+			if req.Filter.Property != f.titleProperty {
+				f.reject(t, w, req.Filter.Property)
+				return
+			}
+			if f.beforeFind != nil {
+				f.beforeFind()
+			}
+			var results []map[string]any
+			for _, p := range f.live() {
+				if p.text() == req.Filter.Title.Equals {
+					results = append(results, f.page(p))
+					break
+				}
+			}
+			f.write(t, w, map[string]any{"results": results, "has_more": false})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/pages":
+			var req CreatePageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode create: %v", err)
+			}
+			title, ok := req.Properties[f.titleProperty]
+			if !ok || len(req.Properties) != 1 {
+				for name := range req.Properties {
+					if name != f.titleProperty {
+						f.reject(t, w, name)
+						return
+					}
+				}
+			}
+			f.mu.Lock()
+			p := fakePerson{id: fmt.Sprintf("person-%d", len(f.pages)+1), title: title.Title}
+			f.pages = append(f.pages, p)
+			f.mu.Unlock()
+			f.write(t, w, f.page(p))
+
+		case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/pages/"):
+			var req TrashPageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode trash: %v", err)
+			}
+			id := strings.TrimPrefix(r.URL.Path, "/pages/")
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			for i := range f.pages {
+				if f.pages[i].id == id {
+					f.pages[i].trashed = req.InTrash
+					f.write(t, w, f.page(f.pages[i]))
+					return
+				}
+			}
+			http.Error(w, `{"object":"error","status":404,"code":"object_not_found"}`, http.StatusNotFound)
+
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.Error(w, "unexpected", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// client returns a new client of the fake that doesn't retry. Clients don't
+// share their read flights, so each acts like a separate process.
+func (f *fakePeople) client() *Client {
+	return NewClient("test-token", WithBaseURL(f.URL), WithMaxAttempts(1))
+}
+
+// reject answers like Notion does for a property the data source lacks
+func (f *fakePeople) reject(t *testing.T, w http.ResponseWriter, property string) {
+	w.WriteHeader(http.StatusBadRequest)
+	f.write(t, w, map[string]any{"object": "error", "status": 400, "code": "validation_error",
+		"message": fmt.Sprintf("Could not find property with name or id: %s", property)})
+}
+
+func (f *fakePeople) write(t *testing.T, w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Errorf("write response: %v", err)
+	}
+}
+
+// live returns the pages not in the trash, oldest first
+func (f *fakePeople) live() []fakePerson {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []fakePerson
+	for _, p := range f.pages {
+		if !p.trashed {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (p fakePerson) text() string {
+	var b strings.Builder
+	for _, rt := range p.title {
+		if rt.Text != nil {
+			b.WriteString(rt.Text.Content)
+		}
+	}
+	return b.String()
+}
+
+func (f *fakePeople) page(p fakePerson) map[string]any {
+	return map[string]any{
+		"object":     "page",
+		"id":         p.id,
+		"properties": map[string]any{f.titleProperty: map[string]any{"type": "title", "title": p.title}},
+	}
+}
+
+func TestUpsertPersonSplitsLongNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		wantParts int
+	}{
+		{"short", "Alice", 1},
+		{"at the limit", strings.Repeat("a", MaxTextLength), 1},
+		{"over 2700 characters", strings.Repeat("b", 2701), 2},
+		{"multibyte", strings.Repeat("é", 2*MaxTextLength+1), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			people := newFakePeople(t, "Name")
+			pg, created, err := people.client().UpsertPersonByTitle(t.Context(), "people", "Name", tt.title, nil, PageContent{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !created {
+				t.Error("created = false, want true")
+			}
+			live := people.live()
+			if len(live) != 1 || live[0].id != pg.ID {
+				t.Fatalf("people pages = %+v, want just %s", live, pg.ID)
+			}
+			title := live[0].title
+			if len(title) != tt.wantParts {
+				t.Errorf("title sent as %d rich text elements, want %d", len(title), tt.wantParts)
+			}
+			for i, rt := range title {
+				if n := utf8.RuneCountInString(rt.Text.Content); n > MaxTextLength {
+					t.Errorf("element %d has %d characters, more than %d", i, n, MaxTextLength)
+				}
+			}
+			if got := live[0].text(); got != tt.title {
+				t.Errorf("elements concatenate to %d bytes, want the %d-byte name", len(got), len(tt.title))
+			}
+		})
+	}
+}
+
+func TestConcurrentUpsertsCreateOnePerson(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    [2]string
+		existing bool
+	}{
+		{"same name", [2]string{"Alice Smith", "Alice Smith"}, false},
+		{"differently spaced", [2]string{"Alice Smith", "  Alice   Smith "}, false},
+		{"already exists", [2]string{"Alice Smith", "Alice Smith"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var existing []string
+			if tt.existing {
+				existing = []string{"Alice Smith"}
+			}
+			people := newFakePeople(t, "Name", existing...)
+			// Hold the first lookup of each upsert until both are in flight, so
+			// both miss (unless the person exists) and create a page.
+			var arrived sync.WaitGroup
+			arrived.Add(2)
+			var finds int
+			var mu sync.Mutex
+			people.beforeFind = func() {
+				mu.Lock()
+				finds++
+				first := finds <= 2
+				mu.Unlock()
+				if first {
+					arrived.Done()
+					arrived.Wait()
+				}
+			}
+
+			type result struct {
+				pg      *Page
+				created bool
+				err     error
+			}
+			results := make([]result, 2)
+			var wg sync.WaitGroup
+			for i, name := range tt.names {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					// A client each, like two processes syncing at once; one client
+					// would collapse the identical lookups into a single request.
+					pg, created, err := people.client().UpsertPersonByTitle(t.Context(), "people", "Name", name, nil, PageContent{})
+					results[i] = result{pg, created, err}
+				}()
+			}
+			wg.Wait()
+
+			created := 0
+			for i, r := range results {
+				if r.err != nil {
+					t.Fatalf("upsert %d: %v", i, r.err)
+				}
+				if r.created {
+					created++
+				}
+			}
+			if results[0].pg.ID != results[1].pg.ID {
+				t.Errorf("upserts returned pages %s and %s, want the same", results[0].pg.ID, results[1].pg.ID)
+			}
+			wantCreated := 1
+			if tt.existing {
+				wantCreated = 0
+			}
+			if created != wantCreated {
+				t.Errorf("%d upserts report created, want %d", created, wantCreated)
+			}
+			live := people.live()
+			if len(live) != 1 || live[0].id != results[0].pg.ID {
+				t.Errorf("live people pages = %+v, want just %s", live, results[0].pg.ID)
+			}
+		})
+	}
+}
+
+func TestPeopleResolverTitleProperty(t *testing.T) {
+	people := newFakePeople(t, "Title", "Alice")
+	r := NewPeopleResolver(people.client(), "people", "Title")
+
+	for _, name := range []string{"Alice", "Bob", "Bob"} {
+		if _, _, err := r.Resolve(t.Context(), name); err != nil {
+			t.Fatalf("resolve %s: %v", name, err)
+		}
+	}
+	live := people.live()
+	if len(live) != 2 || live[0].text() != "Alice" || live[1].text() != "Bob" {
+		t.Errorf("people pages = %+v, want Alice and a single created Bob", live)
+	}
+}
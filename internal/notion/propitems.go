@@ -0,0 +1,119 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PropertyItem is one item of a paginated property value: a single title or
+// rich text segment, relation reference or user. Only the field matching Type is set.
+type PropertyItem struct {
+	Object   string       `json:"object"`
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Title    *RichText    `json:"title,omitempty"`
+	RichText *RichText    `json:"rich_text,omitempty"`
+	Relation *RelationRef `json:"relation,omitempty"`
+	People   *User        `json:"people,omitempty"`
+}
+
+// PropertyItemList is a page of the items of a title, rich_text, relation,
+// people or rollup property
+type PropertyItemList struct {
+	Object     string         `json:"object"`
+	Results    []PropertyItem `json:"results"`
+	HasMore    bool           `json:"has_more"`
+	NextCursor *string        `json:"next_cursor"`
+}
+
+// GetPagePropertyItem retrieves one page of the items of a page property,
+// starting at cursor, or at the first item when cursor is empty. propertyID is
+// the ID from PropertyValue.ID or PropertySchema.ID. Properties that aren't
+// paginated are returned whole; decode them as a PropertyValue.
+func (c *Client) GetPagePropertyItem(ctx context.Context, pageID, propertyID, cursor string) (json.RawMessage, error) {
+	q := url.Values{"page_size": {fmt.Sprint(DefaultPageSize)}}
+	if cursor != "" {
+		q.Set("start_cursor", cursor)
+	}
+	var resp json.RawMessage
+	path := "/pages/" + pageID + "/properties/" + url.PathEscape(propertyID)
+	if err := c.Do(ctx, http.MethodGet, path, q, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CompleteProperty returns p, a property of the page pageID, with all of its
+// values: when the page or query response truncated it (HasMore), the full
+// value is fetched with GetFullProperty. A truncated value without a property
+// ID cannot be completed and is an error rather than quietly partial.
+func (c *Client) CompleteProperty(ctx context.Context, pageID string, p PropertyValue) (PropertyValue, error) {
+	if !p.HasMore {
+		return p, nil
+	}
+	if p.ID == "" {
+		return PropertyValue{}, fmt.Errorf("property of page %s is truncated and has no ID to fetch it by", pageID)
+	}
+	return c.GetFullProperty(ctx, pageID, p.ID)
+}
+
+// GetFullProperty retrieves the complete value of a page property, following
+// the item pagination of title, rich_text, relation and people properties
+// that page and query responses truncate
+func (c *Client) GetFullProperty(ctx context.Context, pageID, propertyID string) (PropertyValue, error) {
+	var (
+		full   PropertyValue
+		cursor string
+		seen   = map[string]bool{}
+	)
+	for {
+		raw, err := c.GetPagePropertyItem(ctx, pageID, propertyID, cursor)
+		if err != nil {
+			return PropertyValue{}, fmt.Errorf("get property %s of page %s: %w", propertyID, pageID, err)
+		}
+
+		var list PropertyItemList
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return PropertyValue{}, fmt.Errorf("decode property %s of page %s: %w", propertyID, pageID, err)
+		}
+		if list.Object != "list" {
+			// Not paginated: the response is the value itself.
+			var v PropertyValue
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return PropertyValue{}, fmt.Errorf("decode property %s of page %s: %w", propertyID, pageID, err)
+			}
+			v.ID = propertyID
+			return v, nil
+		}
+
+		full.ID = propertyID
+		for _, item := range list.Results {
+			full.Type = item.Type
+			switch {
+			case item.Title != nil:
+				full.Title = append(full.Title, *item.Title)
+			case item.RichText != nil:
+				full.RichText = append(full.RichText, *item.RichText)
+			case item.Relation != nil:
+				full.Relation = append(full.Relation, *item.Relation)
+			case item.People != nil:
+				full.People = append(full.People, *item.People)
+			}
+		}
+
+		if err := checkCursor(list.HasMore, list.NextCursor); err != nil {
+			return PropertyValue{}, fmt.Errorf("get property %s of page %s: %w", propertyID, pageID, err)
+		}
+		if !hasNextCursor(list.HasMore, list.NextCursor) {
+			return full, nil
+		}
+		cursor = *list.NextCursor
+		if seen[cursor] {
+			return PropertyValue{}, fmt.Errorf("get property %s of page %s: next cursor %q was already returned", propertyID, pageID, cursor)
+		}
+		seen[cursor] = true
+	}
+}
@@ -0,0 +1,272 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// QueryAll queries a data source and returns the pages of every result page,
+// following next_cursor until exhausted. The PageSize, filter and sorts of req
+// and the filter_properties in qp are kept for every request.
+func (c *Client) QueryAll(ctx context.Context, dataSourceID string, req QueryRequest, qp url.Values) ([]Page, error) {
+	var pages []Page
+	err := c.paginate(ctx, dataSourceID, req, qp, func(resp *QueryResponse) error {
+		pages = append(pages, resp.Results...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// QueryEach queries a data source like QueryAll but calls fn for each page as
+// results arrive instead of buffering them, so memory use stays constant.
+// It stops at the first error returned by fn and returns it unchanged. The
+// context is checked before every round-trip so a long scan can be aborted.
+func (c *Client) QueryEach(ctx context.Context, dataSourceID string, req QueryRequest, qp url.Values, fn func(Page) error) error {
+	return c.paginate(ctx, dataSourceID, req, qp, func(resp *QueryResponse) error {
+		for _, pg := range resp.Results {
+			if err := fn(pg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// QueryBatches queries a data source like QueryEach but hands fn each
+// response as a whole, so callers can act on batch boundaries; resp.Cursor()
+// is where the next batch starts.
+func (c *Client) QueryBatches(ctx context.Context, dataSourceID string, req QueryRequest, qp url.Values, fn func(*QueryResponse) error) error {
+	return c.paginate(ctx, dataSourceID, req, qp, fn)
+}
+
+// paginate runs the query for every cursor and hands each response to fn
+func (c *Client) paginate(ctx context.Context, dataSourceID string, req QueryRequest, qp url.Values, fn func(*QueryResponse) error) error {
+	p, err := c.newPager(dataSourceID, req, qp)
+	if err != nil {
+		return err
+	}
+	return p.each(ctx, fn)
+}
+
+// QueryDatabase queries a database through the legacy /databases/{id}/query
+// endpoint and calls fn for each page like QueryEach does for a data source.
+// It is for clients pinned with WithNotionVersion to an API version before
+// DataSourcesVersion, which have no data sources; QueryAll, QueryEach and
+// QueryBatches of such a Legacy client use it for the IDs they are given.
+// Later versions query a database's data source instead, see
+// ResolveDataSource.
+func (c *Client) QueryDatabase(ctx context.Context, databaseID string, req QueryRequest, qp url.Values, fn func(Page) error) error {
+	p, err := c.newDatabasePager(databaseID, req, qp)
+	if err != nil {
+		return err
+	}
+	return p.each(ctx, func(resp *QueryResponse) error {
+		for _, pg := range resp.Results {
+			if err := fn(pg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// pager fetches the responses of a query one at a time
+type pager struct {
+	c    *Client
+	kind string
+	id   string
+	path string
+	qp   url.Values
+	req  QueryRequest
+	last *QueryResponse
+	seen map[string]bool
+}
+
+func (c *Client) newPager(dataSourceID string, req QueryRequest, qp url.Values) (*pager, error) {
+	if c.Legacy() {
+		return c.newDatabasePager(dataSourceID, req, qp)
+	}
+	return c.newPagerAt("data source", dataSourceID, c.QueryPath(dataSourceID), req, qp)
+}
+
+func (c *Client) newDatabasePager(databaseID string, req QueryRequest, qp url.Values) (*pager, error) {
+	return c.newPagerAt("database", databaseID, "/databases/"+databaseID+"/query", req, qp)
+}
+
+// newPagerAt returns a pager for the query endpoint at path of the kind of
+// object named id, which error messages refer to
+func (c *Client) newPagerAt(kind, id, path string, req QueryRequest, qp url.Values) (*pager, error) {
+	if req.PageSize == 0 {
+		req.PageSize = DefaultPageSize
+	}
+	if err := req.Validate(); err != nil {
+		return nil, queryError(kind, id, nil, err)
+	}
+	return &pager{
+		c:    c,
+		kind: kind,
+		id:   id,
+		path: path,
+		qp:   qp,
+		req:  req,
+		seen: map[string]bool{},
+	}, nil
+}
+
+// each hands every remaining response to fn
+func (p *pager) each(ctx context.Context, fn func(*QueryResponse) error) error {
+	for {
+		resp, err := p.next(ctx)
+		if err != nil || resp == nil {
+			return err
+		}
+		if err := fn(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// next fetches the following response, or returns nil once the previous one
+// was the last. The previous response's cursor is only checked here, so it
+// is handed out even when its cursor turns out to be unusable.
+func (p *pager) next(ctx context.Context) (*QueryResponse, error) {
+	if p.last != nil {
+		if err := p.last.CheckCursor(); err != nil {
+			return nil, queryError(p.kind, p.id, p.req.StartCursor, err)
+		}
+		if !p.last.More() {
+			return nil, nil
+		}
+		next := p.last.Cursor()
+		if p.seen[*next] {
+			return nil, queryError(p.kind, p.id, p.req.StartCursor, fmt.Errorf("next cursor %q was already returned", *next))
+		}
+		p.seen[*next] = true
+		p.req.StartCursor = next
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, queryError(p.kind, p.id, p.req.StartCursor, err)
+	}
+
+	// Trashed pages are only returned when asked for with in_trash; they are
+	// then filtered locally so ArchivedOnly doesn't leak active pages.
+	body := struct {
+		QueryRequest
+		InTrash bool `json:"in_trash,omitempty"`
+	}{p.req, p.req.Archived != ArchivedExclude}
+
+	var resp QueryResponse
+	if err := p.c.Do(ctx, http.MethodPost, p.path, p.qp, body, &resp); err != nil {
+		return nil, queryError(p.kind, p.id, p.req.StartCursor, err)
+	}
+	if p.req.Archived != ArchivedInclude {
+		kept := resp.Results[:0]
+		for _, pg := range resp.Results {
+			if p.req.Archived.keeps(pg) {
+				kept = append(kept, pg)
+			}
+		}
+		resp.Results = kept
+	}
+	p.last = &resp
+	return &resp, nil
+}
+
+// PageIterator steps through the pages of a query one at a time, like
+// bufio.Scanner:
+//
+//	it := client.Iterate(dataSourceID, req, nil)
+//	for it.Next(ctx) {
+//		pg := it.Page()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// The next batch of results is only requested once the current one is used up.
+type PageIterator struct {
+	pager *pager
+	buf   []Page
+	page  Page
+	err   error
+	done  bool
+}
+
+// Iterate returns an iterator over the pages the query matches. Nothing is
+// fetched until the first call to Next.
+func (c *Client) Iterate(dataSourceID string, req QueryRequest, qp url.Values) *PageIterator {
+	p, err := c.newPager(dataSourceID, req, qp)
+	return &PageIterator{pager: p, err: err}
+}
+
+// Next advances to the next page, fetching another batch when needed. It
+// returns false when the pages are exhausted or an error occurred.
+func (it *PageIterator) Next(ctx context.Context) bool {
+	for len(it.buf) == 0 {
+		if it.err != nil || it.done {
+			return false
+		}
+		resp, err := it.pager.next(ctx)
+		switch {
+		case err != nil:
+			it.err = err
+			return false
+		case resp == nil:
+			it.done = true
+			return false
+		}
+		it.buf = resp.Results
+	}
+	it.page, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Page returns the page Next advanced to
+func (it *PageIterator) Page() Page {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration, or nil if the pages
+// were exhausted
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// FilterProperties returns the query parameters that limit returned pages to
+// the named properties, by name or ID, in order and without repeats. With no
+// names it returns nil, which returns every property.
+func FilterProperties(names ...string) url.Values {
+	var props []string
+	seen := map[string]bool{}
+	for _, n := range names {
+		if n != "" && !seen[n] {
+			seen[n] = true
+			props = append(props, n)
+		}
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return url.Values{"filter_properties[]": props}
+}
+
+// FilterPropertiesWithName is FilterProperties with the "Name" title
+// property added first, as a convenience for callers that print page titles.
+func FilterPropertiesWithName(names ...string) url.Values {
+	return FilterProperties(append([]string{"Name"}, names...)...)
+}
+
+// queryError annotates a pagination failure with the cursor it happened at
+func queryError(kind, id string, cursor *string, err error) error {
+	if cursor == nil {
+		return fmt.Errorf("query %s %s: %w", kind, id, err)
+	}
+	return fmt.Errorf("query %s %s at cursor %s: %w", kind, id, *cursor, err)
+}
@@ -0,0 +1,297 @@
+package notion
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// malformedCursor serves a two-batch query whose second batch claims more
+// results without a cursor to fetch them with
+type malformedCursor struct {
+	cursor  *string // next_cursor of the second batch
+	hasMore bool    // has_more of the second batch
+	queries int
+}
+
+func (q *malformedCursor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req QueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q.queries++
+	next := "c1"
+	resp := QueryResponse{Object: "list", Results: []Page{{ID: "a"}}, HasMore: true, NextCursor: &next}
+	if req.StartCursor != nil && *req.StartCursor == "c1" {
+		resp = QueryResponse{Object: "list", Results: []Page{{ID: "b"}}, HasMore: q.hasMore, NextCursor: q.cursor}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestQueryMalformedCursor(t *testing.T) {
+	empty := ""
+	tests := []struct {
+		name    string
+		cursor  *string
+		hasMore bool
+		wantErr error
+	}{
+		{"has_more without cursor", nil, true, ErrMissingCursor},
+		{"has_more with empty cursor", &empty, true, ErrMissingCursor},
+		{"last batch", nil, false, nil},
+	}
+	// Each way of paginating must fetch both batches, then fail rather than stop short.
+	paginate := map[string]func(*Client) (int, error){
+		"QueryAll": func(c *Client) (int, error) {
+			pages, err := c.QueryAll(t.Context(), "ds-1", QueryRequest{}, nil)
+			return len(pages), err
+		},
+		"QueryEach": func(c *Client) (int, error) {
+			n := 0
+			err := c.QueryEach(t.Context(), "ds-1", QueryRequest{}, nil, func(Page) error {
+				n++
+				return nil
+			})
+			return n, err
+		},
+		"Iterate": func(c *Client) (int, error) {
+			it := c.Iterate("ds-1", QueryRequest{}, nil)
+			n := 0
+			for it.Next(t.Context()) {
+				n++
+			}
+			return n, it.Err()
+		},
+	}
+	for _, tt := range tests {
+		for name, query := range paginate {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				q := &malformedCursor{cursor: tt.cursor, hasMore: tt.hasMore}
+				srv := httptest.NewServer(q)
+				defer srv.Close()
+
+				n, err := query(NewClient("test-token", WithBaseURL(srv.URL), WithMaxAttempts(1)))
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if err != nil && !strings.Contains(err.Error(), "HasMore is true but cursor is empty") {
+					t.Errorf("error %q does not describe the malformed response", err)
+				}
+				if q.queries != 2 {
+					t.Errorf("sent %d queries, want 2", q.queries)
+				}
+				if tt.wantErr == nil && n != 2 {
+					t.Errorf("got %d pages, want 2", n)
+				}
+			})
+		}
+	}
+}
+
+func TestQueryRequestPageSize(t *testing.T) {
+	tests := []struct {
+		pageSize int
+		valid    bool
+	}{
+		{-1, false},
+		{0, true},
+		{1, true},
+		{MaxPageSize, true},
+		{MaxPageSize + 1, false},
+		{500, false},
+	}
+	for _, tt := range tests {
+		err := QueryRequest{PageSize: tt.pageSize}.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate with page size %d: err = %v, want valid %v", tt.pageSize, err, tt.valid)
+		}
+		if err != nil && !strings.Contains(err.Error(), "between 1 and 100") {
+			t.Errorf("error %q does not state the limit", err)
+		}
+	}
+}
+
+func TestQuerySendsPageSize(t *testing.T) {
+	var sent []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req QueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sent = append(sent, req.PageSize)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","results":[],"has_more":false}`))
+	}))
+	defer srv.Close()
+	client := NewClient("test-token", WithBaseURL(srv.URL), WithMaxAttempts(1))
+
+	for _, size := range []int{0, MaxPageSize} {
+		if _, err := client.QueryAll(t.Context(), "ds-1", QueryRequest{PageSize: size}, nil); err != nil {
+			t.Fatalf("page size %d: %v", size, err)
+		}
+	}
+	if _, err := client.QueryAll(t.Context(), "ds-1", QueryRequest{PageSize: 500}, nil); err == nil {
+		t.Error("page size 500 was accepted")
+	}
+	if want := []int{DefaultPageSize, MaxPageSize}; !slices.Equal(sent, want) {
+		t.Errorf("sent page sizes %v, want %v: 0 as the default and nothing for 500", sent, want)
+	}
+}
+
+// twoPageQueries answers every query with page a and cursor c1, then with
+// page b, recording the path, start cursor and Notion-Version of each request
+type twoPageQueries struct {
+	paths, cursors, versions []string
+}
+
+func (q *twoPageQueries) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req QueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cursor := ""
+	if req.StartCursor != nil {
+		cursor = *req.StartCursor
+	}
+	q.paths = append(q.paths, r.Method+" "+r.URL.Path)
+	q.cursors = append(q.cursors, cursor)
+	q.versions = append(q.versions, r.Header.Get("Notion-Version"))
+
+	next := "c1"
+	resp := QueryResponse{Object: "list", Results: []Page{{ID: "a"}}, HasMore: true, NextCursor: &next}
+	if cursor == "c1" {
+		resp = QueryResponse{Object: "list", Results: []Page{{ID: "b"}}}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestQueryDatabaseEndpoint(t *testing.T) {
+	const legacy = "2022-06-28"
+	tests := []struct {
+		name     string
+		version  string
+		query    func(*Client, func(Page) error) error
+		wantPath string
+	}{
+		{
+			name:    "QueryDatabase",
+			version: NotionVersion,
+			query: func(c *Client, fn func(Page) error) error {
+				return c.QueryDatabase(t.Context(), "db-1", QueryRequest{}, nil, fn)
+			},
+			wantPath: "POST /databases/db-1/query",
+		},
+		{
+			name:    "QueryEach on a legacy client",
+			version: legacy,
+			query: func(c *Client, fn func(Page) error) error {
+				return c.QueryEach(t.Context(), "db-1", QueryRequest{}, nil, fn)
+			},
+			wantPath: "POST /databases/db-1/query",
+		},
+		{
+			name:    "QueryBatches on a legacy client",
+			version: legacy,
+			query: func(c *Client, fn func(Page) error) error {
+				return c.QueryBatches(t.Context(), "db-1", QueryRequest{}, nil, func(resp *QueryResponse) error {
+					for _, pg := range resp.Results {
+						fn(pg)
+					}
+					return nil
+				})
+			},
+			wantPath: "POST /databases/db-1/query",
+		},
+		{
+			name:    "QueryEach",
+			version: NotionVersion,
+			query: func(c *Client, fn func(Page) error) error {
+				return c.QueryEach(t.Context(), "ds-1", QueryRequest{}, nil, fn)
+			},
+			wantPath: "POST /data_sources/ds-1/query",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &twoPageQueries{}
+			srv := httptest.NewServer(q)
+			defer srv.Close()
+			client := NewClient("test-token", WithBaseURL(srv.URL), WithNotionVersion(tt.version))
+
+			var ids []string
+			err := tt.query(client, func(pg Page) error {
+				ids = append(ids, pg.ID)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(ids, []string{"a", "b"}) {
+				t.Errorf("pages = %q, want a then b", ids)
+			}
+			if want := []string{tt.wantPath, tt.wantPath}; !slices.Equal(q.paths, want) {
+				t.Errorf("requests = %q, want %q", q.paths, want)
+			}
+			if !slices.Equal(q.cursors, []string{"", "c1"}) {
+				t.Errorf("start cursors = %q, want none then c1", q.cursors)
+			}
+			if !slices.Equal(q.versions, []string{tt.version, tt.version}) {
+				t.Errorf("Notion-Version = %q, want %s", q.versions, tt.version)
+			}
+		})
+	}
+}
+
+func TestLegacyClientAddressesDatabases(t *testing.T) {
+	var requests []string
+	var parent Parent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			var req CreatePageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode create: %v", err)
+			}
+			parent = req.Parent
+			w.Write([]byte(`{"object":"page","id":"page-1"}`))
+			return
+		}
+		w.Write([]byte(`{"object":"database","id":"db-1","properties":{"Name":{"id":"title","name":"Name","type":"title","title":{}}}}`))
+	}))
+	defer srv.Close()
+	client := NewClient("test-token", WithBaseURL(srv.URL), WithNotionVersion("2022-06-28"))
+
+	id, err := client.ResolveDataSource(t.Context(), "db-1", ParentAuto)
+	if err != nil || id != "db-1" {
+		t.Fatalf("ResolveDataSource = %q, %v; want db-1 unchanged", id, err)
+	}
+	if _, err := client.ResolveDataSource(t.Context(), "db-1", ParentDataSource); err == nil {
+		t.Error("a data source ID was accepted by a legacy client")
+	}
+	schema, err := client.GetDataSource(t.Context(), "db-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title, ok := schema.TitleProperty(); !ok || title != "Name" {
+		t.Errorf("title property = %q, %v; want Name from the database schema", title, ok)
+	}
+	if _, err := client.CreatePageFull(t.Context(), "db-1", map[string]PropertyValue{"Name": TitleValue("Alice")}, PageContent{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Parent{Type: "database_id", DatabaseID: "db-1"}); parent != want {
+		t.Errorf("parent = %+v, want %+v", parent, want)
+	}
+	if want := []string{"GET /databases/db-1", "POST /pages"}; !slices.Equal(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
@@ -0,0 +1,44 @@
+package notion
+
+// Extractor renders a property value as strings
+type Extractor func(PropertyValue) []string
+
+// ExtractorRegistry overrides how properties are rendered without forking
+// ExtractStrings, e.g. people as emails or relations as titles.
+// Precedence is: property name override > property type override > ExtractStrings.
+// The zero value is ready to use.
+type ExtractorRegistry struct {
+	byName map[string]Extractor
+	byType map[string]Extractor
+}
+
+// RegisterName overrides the rendering of the property with the given name
+func (r *ExtractorRegistry) RegisterName(name string, fn Extractor) {
+	if r.byName == nil {
+		r.byName = map[string]Extractor{}
+	}
+	r.byName[name] = fn
+}
+
+// RegisterType overrides the rendering of every property of the given type
+func (r *ExtractorRegistry) RegisterType(typ string, fn Extractor) {
+	if r.byType == nil {
+		r.byType = map[string]Extractor{}
+	}
+	r.byType[typ] = fn
+}
+
+// RegistryExtract renders the property named name using the most specific
+// extractor registered in reg, falling back to ExtractStrings. A nil registry
+// behaves like an empty one.
+func RegistryExtract(reg *ExtractorRegistry, name string, p PropertyValue) []string {
+	if reg != nil {
+		if fn, ok := reg.byName[name]; ok {
+			return fn(p)
+		}
+		if fn, ok := reg.byType[p.Type]; ok {
+			return fn(p)
+		}
+	}
+	return ExtractStrings(p)
+}
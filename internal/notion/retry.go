@@ -0,0 +1,147 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is how often a request is tried before giving up on transient failures
+	DefaultMaxAttempts = 4
+
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// retryableStatus reports whether a response status is worth retrying:
+// rate limiting and gateway/availability errors.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// conflictCode is Notion's error code for a write that collided with a
+// concurrent edit. Nothing was applied, so the same request can be sent again.
+const conflictCode = "conflict_error"
+
+// retryable reports whether an API error is transient: a retryable status,
+// or a conflict on a write. Validation and other client errors are permanent,
+// as resending the same payload would fail the same way.
+func retryable(e *APIError) bool {
+	if retryableStatus(e.StatusCode) {
+		return true
+	}
+	return e.Method != http.MethodGet && e.StatusCode == http.StatusConflict && e.Code == conflictCode
+}
+
+// IsRetryable reports whether err is a transient failure: an API error with
+// a retryable status or a write conflict, or a network timeout or dropped
+// connection. The Client retries network failures of reads only, since a
+// write that timed out may have been applied and resending a page creation
+// can duplicate the page; callers check this before choosing to resend one.
+// Cancellation and every other error are permanent.
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return retryable(apiErr)
+	}
+	return transientNetError(err)
+}
+
+// transientNetError reports whether err is a network failure that may not
+// recur: a timeout, or a connection reset, aborted or cut off mid-response
+func transientNetError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// sendWithRetry performs the request, retrying transient failures with
+// exponential backoff and jitter, or after the server's Retry-After delay.
+// Network failures are only retried for reads, which cannot have applied
+// anything.
+// The body is a byte slice so every attempt sends it in full.
+func (c *Client) sendWithRetry(ctx context.Context, method, path string, q url.Values, body []byte) (response, error) {
+	attempts := c.maxAttempts
+	if attempts < 1 {
+		attempts = DefaultMaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, path, q, body)
+
+		if err == nil || attempt == attempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var delay time.Duration
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr):
+			if !retryable(apiErr) {
+				return resp, err
+			}
+			delay = apiErr.RetryAfter
+		case !isRead(method, path) || !transientNetError(err):
+			return resp, err
+		}
+		if delay <= 0 {
+			delay = backoff(attempt)
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return resp, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// backoff returns the delay before retry number attempt: exponential growth
+// capped at retryMaxDelay, with jitter spreading retries over the upper half
+func backoff(attempt int) time.Duration {
+	d := retryBaseDelay << (attempt - 1)
+	if d <= 0 || d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
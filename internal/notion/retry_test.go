@@ -0,0 +1,134 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedStatus is one response of a scriptedServer: a status and, for
+// errors, the Retry-After header to send
+type scriptedStatus struct {
+	status     int
+	retryAfter string
+}
+
+// scriptedServer answers the requests in turn with its statuses, then with
+// 200, and records each request's body and arrival time
+type scriptedServer struct {
+	statuses []scriptedStatus
+
+	mu       sync.Mutex
+	bodies   []string
+	arrivals []time.Time
+}
+
+func (s *scriptedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	n := len(s.bodies)
+	s.bodies = append(s.bodies, string(body))
+	s.arrivals = append(s.arrivals, time.Now())
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if n >= len(s.statuses) {
+		w.Write([]byte(`{"object":"page","id":"page-1"}`))
+		return
+	}
+	st := s.statuses[n]
+	if st.retryAfter != "" {
+		w.Header().Set("Retry-After", st.retryAfter)
+	}
+	w.WriteHeader(st.status)
+	fmt.Fprintf(w, `{"object":"error","status":%d,"code":"test_error","message":"scripted"}`, st.status)
+}
+
+func TestSendWithRetry(t *testing.T) {
+	// Short Retry-After delays keep the retried cases fast.
+	const soon = "0.01"
+	tests := []struct {
+		name       string
+		statuses   []scriptedStatus
+		wantStatus int // status of the returned error; 0 for success
+		wantSent   int
+		minGap     time.Duration // least time between the first two requests
+	}{
+		{
+			name:     "429 waits for Retry-After",
+			statuses: []scriptedStatus{{http.StatusTooManyRequests, "0.2"}},
+			wantSent: 2,
+			minGap:   200 * time.Millisecond,
+		},
+		{
+			name:     "503 then success",
+			statuses: []scriptedStatus{{http.StatusServiceUnavailable, soon}},
+			wantSent: 2,
+		},
+		{
+			name: "gives up at max attempts",
+			statuses: []scriptedStatus{
+				{http.StatusBadGateway, soon}, {http.StatusServiceUnavailable, soon}, {http.StatusGatewayTimeout, soon},
+			},
+			wantStatus: http.StatusGatewayTimeout,
+			wantSent:   3,
+		},
+		{"400 fails fast", []scriptedStatus{{http.StatusBadRequest, soon}}, http.StatusBadRequest, 1, 0},
+		{"401 fails fast", []scriptedStatus{{http.StatusUnauthorized, soon}}, http.StatusUnauthorized, 1, 0},
+		{"404 fails fast", []scriptedStatus{{http.StatusNotFound, soon}}, http.StatusNotFound, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &scriptedServer{statuses: tt.statuses}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+			client := NewClient("test-token", WithBaseURL(ts.URL), WithMaxAttempts(3))
+
+			props := map[string]PropertyValue{"Name": TitleValue("Alice"), "Notes": RichTextValue("some notes")}
+			err := client.UpdatePage(t.Context(), "page-1", props)
+
+			var apiErr *APIError
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantStatus != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus):
+				t.Fatalf("err = %v, want an API error with status %d", err, tt.wantStatus)
+			}
+			if len(srv.bodies) != tt.wantSent {
+				t.Fatalf("%d requests sent, want %d", len(srv.bodies), tt.wantSent)
+			}
+			if srv.bodies[0] == "" {
+				t.Fatal("first request had no body")
+			}
+			for i, b := range srv.bodies {
+				if b != srv.bodies[0] {
+					t.Errorf("attempt %d sent %q, want the full body %q again", i+1, b, srv.bodies[0])
+				}
+			}
+			if tt.minGap > 0 {
+				if gap := srv.arrivals[1].Sub(srv.arrivals[0]); gap < tt.minGap {
+					t.Errorf("retried after %s, want at least %s", gap, tt.minGap)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 12; attempt++ {
+		ceiling := min(retryBaseDelay<<(attempt-1), retryMaxDelay)
+		delays := make([]time.Duration, 20)
+		for i := range delays {
+			delays[i] = backoff(attempt)
+		}
+		if lo, hi := slices.Min(delays), slices.Max(delays); lo < ceiling/2 || hi > ceiling {
+			t.Errorf("backoff(%d) ranged %s to %s, want within [%s, %s]", attempt, lo, hi, ceiling/2, ceiling)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package notion
+
+import (
+	"slices"
+	"strings"
+)
+
+// aggregateRollup computes the number a rollup function stands for when the
+// API returned the underlying array instead of the aggregate. Percentages are
+// fractions between 0 and 1, as Notion returns them. ok is false for
+// functions that show values (show_original, show_unique) or aren't numeric.
+func aggregateRollup(r RollupValue) (f float64, ok bool) {
+	switch r.Function {
+	case "count":
+		return float64(len(r.Array)), true
+	case "count_values":
+		n := 0
+		for _, item := range r.Array {
+			n += len(ExtractStrings(item))
+		}
+		return float64(n), true
+	case "unique":
+		var values []string
+		for _, item := range r.Array {
+			values = append(values, ExtractStrings(item)...)
+		}
+		return float64(len(uniqueStrings(values))), true
+	case "empty", "not_empty", "percent_empty", "percent_not_empty":
+		empty := 0
+		for _, item := range r.Array {
+			if len(ExtractStrings(item)) == 0 {
+				empty++
+			}
+		}
+		return countOrShare(r.Function, empty, len(r.Array)), true
+	case "checked", "unchecked", "percent_checked", "percent_unchecked":
+		checked := 0
+		for _, item := range r.Array {
+			if item.Checkbox != nil && *item.Checkbox {
+				checked++
+			}
+		}
+		return countOrShare(r.Function, checked, len(r.Array)), true
+	}
+
+	var nums []float64
+	for _, item := range r.Array {
+		if n := numberOf(item); n != nil {
+			nums = append(nums, *n)
+		}
+	}
+	if len(nums) == 0 {
+		return 0, r.Function == "sum"
+	}
+	switch r.Function {
+	case "sum", "average":
+		sum := 0.0
+		for _, n := range nums {
+			sum += n
+		}
+		if r.Function == "average" {
+			return sum / float64(len(nums)), true
+		}
+		return sum, true
+	case "min":
+		return slices.Min(nums), true
+	case "max":
+		return slices.Max(nums), true
+	case "range":
+		return slices.Max(nums) - slices.Min(nums), true
+	case "median":
+		slices.Sort(nums)
+		mid := len(nums) / 2
+		if len(nums)%2 == 0 {
+			return (nums[mid-1] + nums[mid]) / 2, true
+		}
+		return nums[mid], true
+	}
+	return 0, false
+}
+
+// countOrShare returns the result of a counting rollup function given how
+// many of total items matched empty or checked: the count itself, the count
+// of the others for not_empty and unchecked, or either as a share of total
+func countOrShare(function string, matched, total int) float64 {
+	n := matched
+	if strings.HasSuffix(function, "not_empty") || strings.HasSuffix(function, "unchecked") {
+		n = total - matched
+	}
+	if !strings.HasPrefix(function, "percent_") {
+		return float64(n)
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// numberOf returns the number held by a number or number formula value
+func numberOf(p PropertyValue) *float64 {
+	switch {
+	case p.Type == "number":
+		return p.Number
+	case p.Type == "formula" && p.Formula != nil && p.Formula.Type == "number":
+		return p.Formula.Number
+	}
+	return nil
+}
+
+// uniqueStrings drops repeated values, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
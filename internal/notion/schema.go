@@ -0,0 +1,253 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Schema describes a data source and its properties
+type Schema struct {
+	Object         string                    `json:"object"`
+	ID             string                    `json:"id"`
+	Title          []RichText                `json:"title,omitempty"`
+	Properties     map[string]PropertySchema `json:"properties"`
+	LastEditedTime string                    `json:"last_edited_time,omitempty"`
+}
+
+// PropertySchema describes a single data source property. Only the
+// configuration matching Type is set; types without configuration have none.
+type PropertySchema struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Number      *NumberConfig   `json:"number,omitempty"`
+	Select      *SelectConfig   `json:"select,omitempty"`
+	MultiSelect *SelectConfig   `json:"multi_select,omitempty"`
+	Status      *StatusConfig   `json:"status,omitempty"`
+	Relation    *RelationConfig `json:"relation,omitempty"`
+}
+
+// NumberConfig holds the configuration of a number property
+type NumberConfig struct {
+	Format string `json:"format"`
+}
+
+// SelectConfig holds the options of a select or multi_select property
+type SelectConfig struct {
+	Options []SelectOption `json:"options"`
+}
+
+// StatusConfig holds the options of a status property and the groups they belong to
+type StatusConfig struct {
+	Options []SelectOption `json:"options"`
+	Groups  []StatusGroup  `json:"groups"`
+}
+
+// StatusGroup is a group of status options such as "To-do" or "Complete"
+type StatusGroup struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Color     string   `json:"color,omitempty"`
+	OptionIDs []string `json:"option_ids"`
+}
+
+// Group returns the group, e.g. "In progress", the status option belongs to.
+// The option is matched by ID, or by name when it has none.
+func (sc *StatusConfig) Group(opt SelectOption) (StatusGroup, bool) {
+	id := opt.ID
+	if id == "" {
+		for _, o := range sc.Options {
+			if o.Name == opt.Name {
+				id = o.ID
+				break
+			}
+		}
+	}
+	if id == "" {
+		return StatusGroup{}, false
+	}
+	for _, g := range sc.Groups {
+		if slices.Contains(g.OptionIDs, id) {
+			return g, true
+		}
+	}
+	return StatusGroup{}, false
+}
+
+// RelationConfig holds the target of a relation property
+type RelationConfig struct {
+	DataSourceID string `json:"data_source_id"`
+	DatabaseID   string `json:"database_id,omitempty"`
+	Type         string `json:"type"`
+}
+
+// Options returns the options of a select, multi_select or status property, or nil
+func (p PropertySchema) Options() []SelectOption {
+	switch {
+	case p.Select != nil:
+		return p.Select.Options
+	case p.MultiSelect != nil:
+		return p.MultiSelect.Options
+	case p.Status != nil:
+		return p.Status.Options
+	}
+	return nil
+}
+
+// TitleProperty returns the name of the data source's title property. Every
+// data source has exactly one; ok is false if the schema lists none.
+func (s *Schema) TitleProperty() (name string, ok bool) {
+	for name, ps := range s.Properties {
+		if ps.Type == "title" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// NewOptions returns those of names that are not yet options of the select,
+// multi_select or status property, in order. Writing a select or
+// multi_select value creates them; status options cannot be created.
+func (p PropertySchema) NewOptions(names ...string) []string {
+	var out []string
+	for _, name := range names {
+		if !slices.ContainsFunc(p.Options(), func(o SelectOption) bool { return o.Name == name }) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// PropertyNames returns the names of the data source's properties, sorted
+func (s *Schema) PropertyNames() []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// CheckProperty fails unless the schema has a property called name and,
+// when types are given, it has one of them. A missing property's error lists
+// the properties there are.
+func (s *Schema) CheckProperty(name string, types ...string) error {
+	ps, ok := s.Properties[name]
+	if !ok {
+		return fmt.Errorf("property %q not found in data source %s (it has %s)", name, s.ID, quoteList(s.PropertyNames()))
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	for _, t := range types {
+		if ps.Type == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("property %q has type %q, want %s", name, ps.Type, strings.Join(types, " or "))
+}
+
+// quoteList renders names as a comma-separated list of quoted strings
+func quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = strconv.Quote(n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// GetDataSource retrieves the schema of a data source, or of a database on a
+// Legacy client
+func (c *Client) GetDataSource(ctx context.Context, dataSourceID string) (*Schema, error) {
+	var resp Schema
+	path := "/data_sources/" + dataSourceID
+	if c.Legacy() {
+		// Before data sources, the database itself carries the schema.
+		path = "/databases/" + dataSourceID
+	}
+	if err := c.Do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// readOnlyTypes are property types computed by Notion that cannot be written
+var readOnlyTypes = map[string]bool{
+	"formula":          true,
+	"rollup":           true,
+	"created_time":     true,
+	"created_by":       true,
+	"last_edited_time": true,
+	"last_edited_by":   true,
+	"unique_id":        true,
+}
+
+// UpdatePageProps updates a page like UpdatePage, but first checks every
+// property against the schema and sends only the subfield matching its type,
+// so a stale or over-populated PropertyValue cannot touch anything else.
+func (c *Client) UpdatePageProps(ctx context.Context, pageID string, schema *Schema, properties map[string]PropertyValue) error {
+	clean := make(map[string]PropertyValue, len(properties))
+	for name, v := range properties {
+		ps, ok := schema.Properties[name]
+		if !ok {
+			return fmt.Errorf("property %q is not in the data source schema", name)
+		}
+		if readOnlyTypes[ps.Type] {
+			return fmt.Errorf("property %q has read-only type %q", name, ps.Type)
+		}
+		if v.Type != ps.Type {
+			return fmt.Errorf("property %q has type %q in the schema but a %q value was given", name, ps.Type, v.Type)
+		}
+		if v.Status != nil && v.Status.ID == "" {
+			if missing := ps.NewOptions(v.Status.Name); len(missing) > 0 {
+				return fmt.Errorf("property %q has no status option %q, and the API cannot create status options", name, v.Status.Name)
+			}
+		}
+		w, err := writableValue(v)
+		if err != nil {
+			return fmt.Errorf("property %q: %w", name, err)
+		}
+		clean[name] = w
+	}
+	return c.UpdatePage(ctx, pageID, clean)
+}
+
+// writableValue copies only the subfield of v that matches its type
+func writableValue(v PropertyValue) (PropertyValue, error) {
+	w := PropertyValue{Type: v.Type}
+	switch v.Type {
+	case "title":
+		w.Title = v.Title
+	case "rich_text":
+		w.RichText = v.RichText
+	case "select":
+		w.Select = v.Select
+	case "multi_select":
+		w.MultiSelect = v.MultiSelect
+	case "status":
+		w.Status = v.Status
+	case "people":
+		w.People = v.People
+	case "email":
+		w.Email = v.Email
+	case "url":
+		w.URL = v.URL
+	case "phone_number":
+		w.PhoneNumber = v.PhoneNumber
+	case "number":
+		w.Number = v.Number
+	case "checkbox":
+		w.Checkbox = v.Checkbox
+	case "date":
+		w.Date = v.Date
+	case "relation":
+		w.Relation = v.Relation
+	default:
+		return PropertyValue{}, fmt.Errorf("unsupported property type %q", v.Type)
+	}
+	return w, nil
+}
@@ -0,0 +1,193 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeOptions serves page-1 with a Tags multi_select and a Stage select,
+// adding option names it hasn't seen to its options and echoing them with an
+// ID and the default color, as Notion does. With frozen set it refuses new
+// options like a data source whose options cannot be edited.
+type fakeOptions struct {
+	*httptest.Server
+	frozen bool
+
+	mu      sync.Mutex
+	options []SelectOption
+	values  map[string]PropertyValue
+	updates int
+}
+
+func newFakeOptions(t *testing.T, frozen bool, options ...SelectOption) *fakeOptions {
+	t.Helper()
+	f := &fakeOptions{frozen: frozen, options: options, values: map[string]PropertyValue{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		switch {
+		case r.Method == http.MethodPatch && r.URL.Path == "/pages/page-1":
+			f.updates++
+			var req UpdatePageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode update: %v", err)
+			}
+			for name, v := range req.Properties {
+				for _, o := range SelectedOptions(v) {
+					if o.ID != "" || f.option(o.Name) != nil {
+						continue
+					}
+					if f.frozen {
+						w.WriteHeader(http.StatusBadRequest)
+						f.write(t, w, map[string]any{"object": "error", "status": 400, "code": "validation_error",
+							"message": fmt.Sprintf("Cannot create new select option %q.", o.Name)})
+						return
+					}
+					f.options = append(f.options, SelectOption{ID: fmt.Sprintf("opt-%d", len(f.options)+1), Name: o.Name, Color: "default"})
+				}
+				f.values[name] = v
+			}
+			f.write(t, w, f.page())
+		case r.Method == http.MethodGet && r.URL.Path == "/pages/page-1":
+			f.write(t, w, f.page())
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.Error(w, "unexpected", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// client returns a client of the fake that doesn't retry, so a refused
+// option fails on the first update
+func (f *fakeOptions) client() *Client {
+	return NewClient("test-token", WithBaseURL(f.URL), WithMaxAttempts(1))
+}
+
+func (f *fakeOptions) write(t *testing.T, w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Errorf("write response: %v", err)
+	}
+}
+
+func (f *fakeOptions) option(name string) *SelectOption {
+	i := slices.IndexFunc(f.options, func(o SelectOption) bool { return o.Name == name })
+	if i < 0 {
+		return nil
+	}
+	return &f.options[i]
+}
+
+// page renders page-1 with the stored values resolved to their options
+func (f *fakeOptions) page() Page {
+	props := map[string]PropertyValue{}
+	for name, v := range f.values {
+		out := PropertyValue{Type: v.Type}
+		for _, o := range SelectedOptions(v) {
+			resolved := f.option(o.Name)
+			if resolved == nil {
+				continue
+			}
+			if v.Type == "select" {
+				out.Select = resolved
+			} else {
+				out.MultiSelect = append(out.MultiSelect, *resolved)
+			}
+		}
+		props[name] = out
+	}
+	return Page{Object: "page", ID: "page-1", Properties: props}
+}
+
+func TestSelectOptionsAutoCreate(t *testing.T) {
+	schema := &Schema{ID: "ds-1", Properties: map[string]PropertySchema{
+		"Tags":  {Name: "Tags", Type: "multi_select", MultiSelect: &SelectConfig{Options: []SelectOption{{ID: "opt-1", Name: "old"}}}},
+		"Stage": {Name: "Stage", Type: "select", Select: &SelectConfig{}},
+		"State": {Name: "State", Type: "status", Status: &StatusConfig{Options: []SelectOption{{ID: "s1", Name: "Done"}}}},
+	}}
+	tests := []struct {
+		name     string
+		props    map[string]PropertyValue
+		frozen   bool
+		wantTags []string // option names of Tags after the update, all with IDs
+		wantErr  string   // substring of the error; empty for success
+		wantSent bool
+	}{
+		{
+			name:     "new and existing options",
+			props:    map[string]PropertyValue{"Tags": MultiSelectValue("old", "new"), "Stage": SelectValue("Draft")},
+			wantTags: []string{"old", "new"},
+			wantSent: true,
+		},
+		{
+			name:     "data source refuses new options",
+			props:    map[string]PropertyValue{"Tags": MultiSelectValue("new")},
+			frozen:   true,
+			wantErr:  "validation_error",
+			wantSent: true,
+		},
+		{
+			name:    "comma in name",
+			props:   map[string]PropertyValue{"Tags": MultiSelectValue("a,b")},
+			wantErr: "comma",
+		},
+		{
+			name:    "name too long",
+			props:   map[string]PropertyValue{"Stage": SelectValue(strings.Repeat("x", MaxOptionNameLength+1))},
+			wantErr: "longer than",
+		},
+		{
+			name:    "empty name",
+			props:   map[string]PropertyValue{"Tags": MultiSelectValue("")},
+			wantErr: "without a name",
+		},
+		{
+			name:    "unknown status option",
+			props:   map[string]PropertyValue{"State": {Type: "status", Status: &SelectOption{Name: "Blocked"}}},
+			wantErr: "cannot create status options",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeOptions(t, tt.frozen, SelectOption{ID: "opt-1", Name: "old", Color: "blue"})
+			client := fake.client()
+
+			err := client.UpdatePageProps(t.Context(), "page-1", schema, tt.props)
+			if sent := fake.updates > 0; sent != tt.wantSent {
+				t.Errorf("update sent = %v, want %v", sent, tt.wantSent)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pg, err := client.GetPage(t.Context(), "page-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, o := range SelectedOptions(pg.Properties["Tags"]) {
+				if o.ID == "" || o.Color == "" {
+					t.Errorf("option %q came back without an ID or color", o.Name)
+				}
+				names = append(names, o.Name)
+			}
+			if !slices.Equal(names, tt.wantTags) {
+				t.Errorf("Tags = %q, want %q", names, tt.wantTags)
+			}
+		})
+	}
+}
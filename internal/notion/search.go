@@ -0,0 +1,124 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Object types a search can be restricted to
+const (
+	SearchPages       = "page"
+	SearchDataSources = "data_source"
+)
+
+// SearchFilter restricts search results to one object type. The zero value
+// returns every object type.
+type SearchFilter struct {
+	Property string `json:"property"`
+	Value    string `json:"value"`
+}
+
+// ObjectFilter restricts a search to pages or data sources
+func ObjectFilter(object string) SearchFilter {
+	return SearchFilter{Property: "object", Value: object}
+}
+
+// SearchRequest represents a search request
+type SearchRequest struct {
+	Query       string        `json:"query,omitempty"`
+	Filter      *SearchFilter `json:"filter,omitempty"`
+	PageSize    int           `json:"page_size,omitempty"`
+	StartCursor *string       `json:"start_cursor,omitempty"`
+}
+
+// SearchResult is a page or a data source returned by a search. Exactly one
+// of Page and DataSource is set, matching Object.
+type SearchResult struct {
+	Object     string
+	Page       *Page
+	DataSource *Schema
+}
+
+// ID returns the ID of the page or data source
+func (r SearchResult) ID() string {
+	if r.Page != nil {
+		return r.Page.ID
+	}
+	if r.DataSource != nil {
+		return r.DataSource.ID
+	}
+	return ""
+}
+
+// Title returns the plain text title of the page or data source
+func (r SearchResult) Title() string {
+	if r.Page != nil {
+		return r.Page.Title()
+	}
+	if r.DataSource != nil {
+		return concatRichText(r.DataSource.Title)
+	}
+	return ""
+}
+
+func (r *SearchResult) UnmarshalJSON(b []byte) error {
+	var head struct {
+		Object string `json:"object"`
+	}
+	if err := json.Unmarshal(b, &head); err != nil {
+		return err
+	}
+	r.Object = head.Object
+	switch head.Object {
+	case SearchPages:
+		r.Page = &Page{}
+		return json.Unmarshal(b, r.Page)
+	case SearchDataSources:
+		r.DataSource = &Schema{}
+		return json.Unmarshal(b, r.DataSource)
+	}
+	return nil
+}
+
+type searchResponse struct {
+	Results    []SearchResult `json:"results"`
+	HasMore    bool           `json:"has_more"`
+	NextCursor *string        `json:"next_cursor"`
+}
+
+// Search returns every page and data source shared with the integration whose
+// title matches query, following next_cursor until exhausted. An empty query
+// matches everything.
+func (c *Client) Search(ctx context.Context, query string, filter SearchFilter) ([]SearchResult, error) {
+	req := SearchRequest{Query: query, PageSize: DefaultPageSize}
+	if filter != (SearchFilter{}) {
+		req.Filter = &filter
+	}
+
+	var results []SearchResult
+	seen := map[string]bool{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("search: %w", err)
+		}
+		var resp searchResponse
+		if err := c.Do(ctx, http.MethodPost, "/search", nil, req, &resp); err != nil {
+			return nil, fmt.Errorf("search: %w", err)
+		}
+		results = append(results, resp.Results...)
+
+		if err := checkCursor(resp.HasMore, resp.NextCursor); err != nil {
+			return nil, fmt.Errorf("search: %w", err)
+		}
+		if !hasNextCursor(resp.HasMore, resp.NextCursor) {
+			return results, nil
+		}
+		if seen[*resp.NextCursor] {
+			return nil, fmt.Errorf("search: next cursor %q was already returned", *resp.NextCursor)
+		}
+		seen[*resp.NextCursor] = true
+		req.StartCursor = resp.NextCursor
+	}
+}
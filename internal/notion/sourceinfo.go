@@ -0,0 +1,67 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SourceInfo is what identifies a data source or database to a person: its
+// title and when it was last edited
+type SourceInfo struct {
+	ID    string
+	Title string
+	// LastEdited is zero when Notion didn't report it
+	LastEdited time.Time
+}
+
+// String renders the info as e.g. `"Chronicles" (ID), last edited 2025-09-03 14:05 UTC`
+func (i SourceInfo) String() string {
+	title := "untitled"
+	if i.Title != "" {
+		title = fmt.Sprintf("%q", i.Title)
+	}
+	s := fmt.Sprintf("%s (%s)", title, i.ID)
+	if !i.LastEdited.IsZero() {
+		s += ", last edited " + i.LastEdited.UTC().Format("2006-01-02 15:04 MST")
+	}
+	return s
+}
+
+// Info returns the title and last edit time of the data source, so callers
+// holding a schema need no further request
+func (s *Schema) Info() SourceInfo {
+	return sourceInfo(s.ID, s.Title, s.LastEditedTime)
+}
+
+// Info returns the title and last edit time of the database
+func (db *Database) Info() SourceInfo {
+	return sourceInfo(db.ID, db.Title, db.LastEditedTime)
+}
+
+func sourceInfo(id string, title []RichText, lastEdited string) SourceInfo {
+	info := SourceInfo{ID: id, Title: strings.TrimSpace(plainText(title))}
+	if t, err := time.Parse(time.RFC3339, lastEdited); err == nil {
+		info.LastEdited = t
+	}
+	return info
+}
+
+// GetSourceInfo retrieves the title and last edit time of id, read as a data
+// source or, when Notion doesn't find one, as a database
+func (c *Client) GetSourceInfo(ctx context.Context, id string) (SourceInfo, error) {
+	schema, err := c.GetDataSource(ctx, id)
+	if err == nil {
+		return schema.Info(), nil
+	}
+	if !HasStatus(err, http.StatusNotFound) && !HasStatus(err, http.StatusBadRequest) {
+		return SourceInfo{}, err
+	}
+	db, err := c.GetDatabase(ctx, id)
+	if err != nil {
+		return SourceInfo{}, fmt.Errorf("resolve %s as a database: %w", id, err)
+	}
+	return db.Info(), nil
+}
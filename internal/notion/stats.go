@@ -0,0 +1,96 @@
+package notion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Stats counts the HTTP requests a client sent. Retries count as separate
+// requests; reads served from the cache or shared with an identical in-flight
+// read are not counted, as they never reach the API.
+type Stats struct {
+	// Total is the number of requests sent
+	Total int
+	// Methods counts requests by HTTP method, e.g. "GET"
+	Methods map[string]int
+	// Statuses counts requests by status class, e.g. "2xx" or "4xx", and
+	// "error" for requests that got no response
+	Statuses map[string]int
+}
+
+// String summarizes the requests by method, e.g. "312 GETs, 48 POSTs, 90 PATCHes"
+func (s Stats) String() string {
+	if s.Total == 0 {
+		return "no requests"
+	}
+	methods := make([]string, 0, len(s.Methods))
+	for m := range s.Methods {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if s.Methods[methods[i]] != s.Methods[methods[j]] {
+			return s.Methods[methods[i]] > s.Methods[methods[j]]
+		}
+		return methods[i] < methods[j]
+	})
+
+	parts := make([]string, len(methods))
+	for i, m := range methods {
+		n := s.Methods[m]
+		switch {
+		case n == 1:
+			parts[i] = "1 " + m
+		case strings.HasSuffix(m, "H"):
+			parts[i] = fmt.Sprintf("%d %ses", n, m)
+		default:
+			parts[i] = fmt.Sprintf("%d %ss", n, m)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// requestStats accumulates Stats; it is safe for concurrent use
+type requestStats struct {
+	mu       sync.Mutex
+	total    int
+	methods  map[string]int
+	statuses map[string]int
+}
+
+// record counts a request that got status, or none when status is 0
+func (s *requestStats) record(method string, status int) {
+	class := "error"
+	if status > 0 {
+		class = fmt.Sprintf("%dxx", status/100)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.methods == nil {
+		s.methods = map[string]int{}
+		s.statuses = map[string]int{}
+	}
+	s.total++
+	s.methods[method]++
+	s.statuses[class]++
+}
+
+// Stats returns the number of requests sent so far by method and status class
+func (c *Client) Stats() Stats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+	st := Stats{
+		Total:    c.stats.total,
+		Methods:  make(map[string]int, len(c.stats.methods)),
+		Statuses: make(map[string]int, len(c.stats.statuses)),
+	}
+	for k, v := range c.stats.methods {
+		st.Methods[k] = v
+	}
+	for k, v := range c.stats.statuses {
+		st.Statuses[k] = v
+	}
+	return st
+}
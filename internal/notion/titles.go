@@ -0,0 +1,103 @@
+package notion
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// resolveConcurrency bounds the parallel page retrievals of ResolveTitles
+const resolveConcurrency = 8
+
+// titleCache remembers resolved page titles for the lifetime of a client
+type titleCache struct {
+	mu     sync.Mutex
+	titles map[string]string
+}
+
+// Title returns the plain text of the page's title property
+func (p Page) Title() string {
+	for _, prop := range p.Properties {
+		if prop.Type == "title" {
+			return ExtractString(prop)
+		}
+	}
+	return ""
+}
+
+// ResolveTitles returns the title of each page ID, e.g. to render relation
+// targets by name. Notion's query endpoint cannot filter on page IDs, so
+// uncached pages are retrieved individually with bounded concurrency; titles
+// are cached on the client. Pages that don't exist or aren't shared with the
+// integration resolve to their own ID.
+func (c *Client) ResolveTitles(ctx context.Context, pageIDs []string) (map[string]string, error) {
+	out := make(map[string]string, len(pageIDs))
+	var missing []string
+
+	c.titles.mu.Lock()
+	for _, id := range pageIDs {
+		if t, ok := c.titles.titles[id]; ok {
+			out[id] = t
+		} else if _, queued := out[id]; !queued {
+			out[id] = id
+			missing = append(missing, id)
+		}
+	}
+	c.titles.mu.Unlock()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, resolveConcurrency)
+	)
+	for _, id := range missing {
+		// A cancelled run stops here rather than wait for a free slot.
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			title := id
+			pg, err := c.GetPage(ctx, id)
+			switch {
+			case err == nil:
+				if t := pg.Title(); t != "" {
+					title = t
+				}
+			case HasStatus(err, http.StatusNotFound), HasStatus(err, http.StatusForbidden):
+				// Unresolvable page: keep the ID.
+			default:
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+
+			mu.Lock()
+			out[id] = title
+			mu.Unlock()
+
+			c.titles.mu.Lock()
+			if c.titles.titles == nil {
+				c.titles.titles = map[string]string{}
+			}
+			c.titles.titles[id] = title
+			c.titles.mu.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
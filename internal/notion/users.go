@@ -0,0 +1,140 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type userList struct {
+	Results    []User  `json:"results"`
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// ListUsers returns every user of the workspace, following next_cursor until
+// exhausted. Guests are not included by the API.
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	q := url.Values{"page_size": {strconv.Itoa(DefaultPageSize)}}
+	var users []User
+	seen := map[string]bool{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		var resp userList
+		if err := c.Do(ctx, http.MethodGet, "/users", q, nil, &resp); err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		users = append(users, resp.Results...)
+
+		if err := checkCursor(resp.HasMore, resp.NextCursor); err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		if !hasNextCursor(resp.HasMore, resp.NextCursor) {
+			return users, nil
+		}
+		if seen[*resp.NextCursor] {
+			return nil, fmt.Errorf("list users: next cursor %q was already returned", *resp.NextCursor)
+		}
+		seen[*resp.NextCursor] = true
+		q.Set("start_cursor", *resp.NextCursor)
+	}
+}
+
+// GetMe returns the bot user of the integration the token belongs to
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	var resp User
+	if err := c.Do(ctx, http.MethodGet, "/users/me", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UserNames maps the ID of every named user to its name
+func UserNames(users []User) map[string]string {
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		if u.Name != "" {
+			names[u.ID] = u.Name
+		}
+	}
+	return names
+}
+
+// PeopleNames returns the names of the users in a people, created_by or
+// last_edited_by property, taking them from names when the inline name is
+// missing and falling back to the user ID otherwise
+func PeopleNames(p PropertyValue, names map[string]string) []string {
+	users := p.People
+	for _, u := range []*User{p.CreatedBy, p.LastEditedBy} {
+		if u != nil {
+			users = append(users, *u)
+		}
+	}
+	out := make([]string, 0, len(users))
+	for _, u := range users {
+		if u.Name == "" && names[u.ID] != "" {
+			u.Name = names[u.ID]
+		}
+		out = append(out, userName(u))
+	}
+	return out
+}
+
+// UserResolver maps email addresses to the IDs of workspace users, for writing
+// people properties. The users are listed once, on the first lookup.
+type UserResolver struct {
+	client *Client
+
+	mu      sync.Mutex
+	byEmail map[string]string
+}
+
+// NewUserResolver returns a resolver listing the users visible to client
+func NewUserResolver(client *Client) *UserResolver {
+	return &UserResolver{client: client}
+}
+
+// Resolve returns the ID of the person user with the given email, compared
+// case-insensitively. It fails if no such user exists.
+func (r *UserResolver) Resolve(ctx context.Context, email string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.byEmail == nil {
+		users, err := r.client.ListUsers(ctx)
+		if err != nil {
+			return "", err
+		}
+		r.byEmail = make(map[string]string, len(users))
+		for _, u := range users {
+			if u.Person != nil && u.Person.Email != "" {
+				r.byEmail[strings.ToLower(u.Person.Email)] = u.ID
+			}
+		}
+	}
+
+	id, ok := r.byEmail[strings.ToLower(strings.TrimSpace(email))]
+	if !ok {
+		return "", fmt.Errorf("no user with email %q", email)
+	}
+	return id, nil
+}
+
+// ResolveAll resolves every email and returns the user IDs in the same order
+func (r *UserResolver) ResolveAll(ctx context.Context, emails ...string) ([]string, error) {
+	ids := make([]string, 0, len(emails))
+	for _, e := range emails {
+		id, err := r.Resolve(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,225 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TitleValue builds a title property value
+func TitleValue(s string) PropertyValue {
+	return PropertyValue{Type: "title", Title: textRichText(s)}
+}
+
+// RichTextValue builds a rich_text property value
+func RichTextValue(s string) PropertyValue {
+	return PropertyValue{Type: "rich_text", RichText: textRichText(s)}
+}
+
+// SelectValue builds a select property value. An option name the data source
+// doesn't have yet is added to it by Notion when the value is written, in
+// the default color; see PropertySchema.NewOptions.
+func SelectValue(name string) PropertyValue {
+	return PropertyValue{Type: "select", Select: &SelectOption{Name: name}}
+}
+
+// MultiSelectValue builds a multi_select property value. Like SelectValue,
+// options that don't exist yet are created when the value is written.
+func MultiSelectValue(names ...string) PropertyValue {
+	opts := make([]SelectOption, 0, len(names))
+	for _, n := range names {
+		opts = append(opts, SelectOption{Name: n})
+	}
+	return PropertyValue{Type: "multi_select", MultiSelect: opts}
+}
+
+// NumberValue builds a number property value
+func NumberValue(f float64) PropertyValue {
+	return PropertyValue{Type: "number", Number: &f}
+}
+
+// CheckboxValue builds a checkbox property value
+func CheckboxValue(b bool) PropertyValue {
+	return PropertyValue{Type: "checkbox", Checkbox: &b}
+}
+
+// URLValue builds a url property value
+func URLValue(s string) PropertyValue {
+	return PropertyValue{Type: "url", URL: &s}
+}
+
+// EmailValue builds an email property value
+func EmailValue(s string) PropertyValue {
+	return PropertyValue{Type: "email", Email: &s}
+}
+
+// PhoneNumberValue builds a phone_number property value
+func PhoneNumberValue(s string) PropertyValue {
+	return PropertyValue{Type: "phone_number", PhoneNumber: &s}
+}
+
+// ParseEmailValue builds an email property value after checking that s is a
+// bare address like "alice@example.com"
+func ParseEmailValue(s string) (PropertyValue, error) {
+	s = strings.TrimSpace(s)
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return PropertyValue{}, fmt.Errorf("invalid email address %q", s)
+	}
+	return EmailValue(s), nil
+}
+
+// ParseURLValue builds a url property value after checking that s is an
+// absolute URL with a scheme and host
+func ParseURLValue(s string) (PropertyValue, error) {
+	s = strings.TrimSpace(s)
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return PropertyValue{}, fmt.Errorf("invalid URL %q: expected an absolute URL like https://example.com", s)
+	}
+	return URLValue(s), nil
+}
+
+// ParsePhoneNumberValue builds a phone_number property value after checking
+// that s has digits and otherwise only +, -, ., parentheses and spaces.
+// Notion stores phone numbers as free text, so the format is not checked.
+func ParsePhoneNumberValue(s string) (PropertyValue, error) {
+	s = strings.TrimSpace(s)
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case strings.ContainsRune("+-.() ", r):
+		default:
+			return PropertyValue{}, fmt.Errorf("invalid phone number %q: unexpected %q", s, r)
+		}
+	}
+	if digits == 0 {
+		return PropertyValue{}, fmt.Errorf("invalid phone number %q: no digits", s)
+	}
+	return PhoneNumberValue(s), nil
+}
+
+// RelationValue builds a relation property value from page IDs
+func RelationValue(ids ...string) PropertyValue {
+	refs := make([]RelationRef, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, RelationRef{ID: id})
+	}
+	return PropertyValue{Type: "relation", Relation: refs}
+}
+
+// PeopleValue builds a people property value from user IDs. Use a
+// UserResolver to look the IDs up by email.
+func PeopleValue(userIDs ...string) PropertyValue {
+	users := make([]User, 0, len(userIDs))
+	for _, id := range userIDs {
+		users = append(users, User{ID: id})
+	}
+	return PropertyValue{Type: "people", People: users}
+}
+
+// MaxOptionNameLength is the longest select option name Notion accepts
+const MaxOptionNameLength = 100
+
+// checkOptionName rejects option names Notion cannot create: empty ones,
+// overly long ones and, as commas separate options, ones with a comma
+func checkOptionName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("option without a name or ID")
+	case strings.Contains(name, ","):
+		return fmt.Errorf("option %q contains a comma, which option names cannot", name)
+	case utf8.RuneCountInString(name) > MaxOptionNameLength:
+		return fmt.Errorf("option %q is longer than %d characters", name, MaxOptionNameLength)
+	}
+	return nil
+}
+
+// checkPropertyValues rejects people and relation values referencing an empty
+// ID and select options that cannot be created, which the API would refuse
+// with a less helpful validation error
+func checkPropertyValues(props map[string]PropertyValue) error {
+	for name, v := range props {
+		opts := slices.Clone(v.MultiSelect)
+		for _, o := range []*SelectOption{v.Select, v.Status} {
+			if o != nil {
+				opts = append(opts, *o)
+			}
+		}
+		for _, o := range opts {
+			if o.ID != "" {
+				continue
+			}
+			if err := checkOptionName(o.Name); err != nil {
+				return fmt.Errorf("property %q: %w", name, err)
+			}
+		}
+		for _, u := range v.People {
+			if u.ID == "" {
+				return fmt.Errorf("property %q: people value with an empty user ID", name)
+			}
+		}
+		for _, r := range v.Relation {
+			if r.ID == "" {
+				return fmt.Errorf("property %q: relation value with an empty page ID", name)
+			}
+		}
+	}
+	return nil
+}
+
+// DateStringValue builds a date property value from ISO 8601 strings.
+// An empty end leaves the date without a range.
+func DateStringValue(start, end string) PropertyValue {
+	d := &DateValue{Start: start}
+	if end != "" {
+		d.End = &end
+	}
+	return PropertyValue{Type: "date", Date: d}
+}
+
+// DateTimeValue builds a date property value from times, formatted as RFC 3339
+// with their offset. A nil end leaves the date without a range. (DateValue is
+// the name of the date payload type, hence the different name.)
+func DateTimeValue(start time.Time, end *time.Time) PropertyValue {
+	d := &DateValue{Start: start.Format(time.RFC3339)}
+	if end != nil {
+		e := end.Format(time.RFC3339)
+		d.End = &e
+	}
+	return PropertyValue{Type: "date", Date: d}
+}
+
+// MaxTextLength is the most characters Notion accepts in the content of a
+// single rich text element
+const MaxTextLength = 2000
+
+// textRichText builds plain rich text from s, split into as many elements as
+// needed to keep each under MaxTextLength. Splits fall between characters, so
+// the elements concatenate back to s.
+func textRichText(s string) []RichText {
+	var out []RichText
+	for {
+		chunk := s
+		n := 0
+		for i := range s {
+			if n == MaxTextLength {
+				chunk = s[:i]
+				break
+			}
+			n++
+		}
+		out = append(out, RichText{Type: "text", Text: &TextContent{Content: chunk}})
+		s = s[len(chunk):]
+		if s == "" {
+			return out
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package notion
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildersRoundTrip(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	tests := []struct {
+		name  string
+		value PropertyValue
+		want  string
+	}{
+		{"title", TitleValue("Alice"), `{"type":"title","title":[{"type":"text","text":{"content":"Alice"}}]}`},
+		{"rich_text", RichTextValue("note"), `{"type":"rich_text","rich_text":[{"type":"text","text":{"content":"note"}}]}`},
+		{"select", SelectValue("Done"), `{"type":"select","select":{"name":"Done"}}`},
+		{"multi_select", MultiSelectValue("a", "b"), `{"type":"multi_select","multi_select":[{"name":"a"},{"name":"b"}]}`},
+		{"number", NumberValue(2.5), `{"type":"number","number":2.5}`},
+		{"checkbox", CheckboxValue(false), `{"type":"checkbox","checkbox":false}`},
+		{"date", DateTimeValue(start, &end), `{"type":"date","date":{"start":"2024-03-01T10:00:00Z","end":"2024-03-01T11:30:00Z"}}`},
+		{"date without end", DateTimeValue(start, nil), `{"type":"date","date":{"start":"2024-03-01T10:00:00Z","end":null}}`},
+		{"url", URLValue("https://example.com"), `{"type":"url","url":"https://example.com"}`},
+		{"email", EmailValue("alice@example.com"), `{"type":"email","email":"alice@example.com"}`},
+		{"relation", RelationValue("p1", "p2"), `{"type":"relation","relation":[{"id":"p1"},{"id":"p2"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("marshalled %s, want %s", b, tt.want)
+			}
+			var back PropertyValue
+			if err := json.Unmarshal(b, &back); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(back, tt.value) {
+				t.Errorf("round trip = %+v, want %+v", back, tt.value)
+			}
+		})
+	}
+}
+
+func TestContactBuilders(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func(string) (PropertyValue, error)
+		in      string
+		want    PropertyValue
+		wantErr string // empty when in is valid
+	}{
+		{"email", ParseEmailValue, " alice@example.com ", EmailValue("alice@example.com"), ""},
+		{"email with display name", ParseEmailValue, "Alice <alice@example.com>", PropertyValue{}, "invalid email address"},
+		{"email without domain", ParseEmailValue, "alice", PropertyValue{}, "invalid email address"},
+		{"empty email", ParseEmailValue, "", PropertyValue{}, "invalid email address"},
+		{"url", ParseURLValue, "https://example.com/a?b=c", URLValue("https://example.com/a?b=c"), ""},
+		{"url without scheme", ParseURLValue, "example.com", PropertyValue{}, "expected an absolute URL"},
+		{"url without host", ParseURLValue, "mailto:alice@example.com", PropertyValue{}, "expected an absolute URL"},
+		{"phone", ParsePhoneNumberValue, "+1 (555) 123-45.67", PhoneNumberValue("+1 (555) 123-45.67"), ""},
+		{"phone with letters", ParsePhoneNumberValue, "555-CALL", PropertyValue{}, `unexpected 'C'`},
+		{"phone without digits", ParsePhoneNumberValue, "+-()", PropertyValue{}, "no digits"},
+		{"empty phone", ParsePhoneNumberValue, " ", PropertyValue{}, "no digits"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parse %q: err = %v, want one containing %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parse %q = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
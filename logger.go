@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Log levels, from most to least verbose
+const (
+	levelDebug = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+var levelNames = [...]string{"debug", "info", "warn", "error"}
+
+// logger writes progress messages at or above its level to w. Data output
+// (records, previews) goes to stdout instead, so logs never mix into it.
+type logger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level int
+}
+
+// logs is the logger used throughout the CLI; -verbose and -quiet set its level
+var logs = &logger{w: os.Stderr, level: levelInfo}
+
+func (l *logger) logf(level int, format string, args ...any) {
+	if level < l.level {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level == levelInfo {
+		fmt.Fprintf(l.w, format, args...)
+		return
+	}
+	fmt.Fprintf(l.w, levelNames[level]+": "+format, args...)
+}
+
+func debugf(format string, args ...any) { logs.logf(levelDebug, format, args...) }
+func infof(format string, args ...any)  { logs.logf(levelInfo, format, args...) }
+func warnf(format string, args ...any)  { logs.logf(levelWarn, format, args...) }
+func errorf(format string, args ...any) { logs.logf(levelError, format, args...) }
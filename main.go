@@ -6,167 +6,530 @@ import (
 	"flag"
 	"fmt"
 	"net/http"
-	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"notion-tools/internal/notion"
 )
 
+// Used when neither a flag nor its environment variable names the data source or people database
 const (
 	NotionChroniclesDataSourceID = "dc70f391-ee49-4e69-9aad-52c6ac9b16c0"
 	NotionPeopleDatabaseID       = "2e7e1d14-ea06-80f8-8635-000bc244940f"
 
 	defaultWhoPropName = "Who"
+	defaultRelation    = "People"
+	defaultTitleField  = "Name" // assumed when the schema lists no title property
+	defaultSeparators  = ", "
+
+	modeSync      = "sync"
+	modeImportCSV = "import-csv"
+	modeExport    = "export"
+	modeDumpJSON  = "dump-json"
+	modeSources   = "list-sources"
+	modeCheck     = "check"
+
+	modeCreatePeople  = "create-people"
+	modeLinkRelations = "link-relations"
 )
 
+// config holds the parsed command line options
+type config struct {
+	token          string
+	notionVersion  string
+	dataSource     string
+	sourceParent   string
+	peopleDB       string
+	peopleParent   string
+	mode           string
+	fields         []string
+	relationField  string
+	titleField     string
+	peopleTitle    string
+	file           string
+	idColumn       string
+	types          string
+	dryRun         bool
+	concurrency    int
+	sinceFile      string
+	since          time.Time
+	checkpoint     string
+	overlap        time.Duration
+	timeout        time.Duration
+	lookupTimeout  time.Duration
+	output         string
+	columns        []string
+	multiSep       string
+	location       *time.Location
+	numberFormat   bool
+	reportHTML     string
+	diffReport     string
+	diffFormat     string
+	out            string
+	retryConflicts bool
+	force          bool
+	aliases        *nameMap
+	relationTitles bool
+	preview        int
+	manifest       string
+	timestamps     bool
+	sorts          []notion.Sort
+	prewarm        bool
+	logLevel       int
+	personIcon     string
+	separators     []string
+	transforms     []nameTransform
+	sources        []syncSource
+	defaultProps   defaultPropList
+}
+
 // ---- Main ----
 
 func main() {
+	cfg, err := parseFlags()
+	if err != nil {
+		fatal(err)
+	}
+	logs.level = cfg.logLevel
+	if err := run(context.Background(), cfg); err != nil {
+		fatal(err)
+	}
+}
+
+func parseFlags() (config, error) {
+	var (
+		tokenFlag   = flag.String("token", "", "Notion integration token (or set NOTION_TOKEN)")
+		dataSource  = flag.String("data-source", "", "ID of the data source to read (or set NOTION_DATA_SOURCE_ID)")
+		sourceKind  = flag.String("data-source-parent", notion.ParentAuto, "Whether -data-source and -source are data_source or database IDs (default: auto, detected)")
+		versionFlag = flag.String("notion-version", notion.NotionVersion, "Notion API version to send; before "+notion.DataSourcesVersion+" all IDs are database IDs")
+		peopleDB    = flag.String("people-db", "", "ID of the people data source (or set NOTION_PEOPLE_DB_ID)")
+		peopleKind  = flag.String("people-parent", notion.ParentAuto, "Whether -people-db is a data_source or a database ID (default: auto, detected)")
+		modeFlag    = flag.String("mode", modeSync, "Mode to run: sync, create-people, link-relations, export, import-csv, dump-json, list-sources or check")
+		fieldName   = flag.String("field", defaultWhoPropName, "Property to extract persons from, or a comma-separated list of them")
+		relField    = flag.String("relation-field", defaultRelation, "Relation property to set to the resolved people pages")
+		titleFlag   = flag.String("title-field", "", "Title property of the data source (default: detected from the schema)")
+		peopleTitle = flag.String("people-title-field", "", "Title property of the people data source (default: detected from the schema)")
+		fileFlag    = flag.String("file", "", "CSV file to read in import-csv mode")
+		idColumn    = flag.String("id-column", "id", "CSV column holding the page ID in import-csv mode")
+		typesFlag   = flag.String("types", "", "Property types for CSV columns in import-csv mode, e.g. Status=select,Score=number (default: from the data source schema)")
+		dryRun      = flag.Bool("dry-run", false, "Read as usual but only print what would be created or updated, without writing anything")
+		concurrency = flag.Int("concurrency", 4, "Maximum number of pages synced or rows imported concurrently")
+		sinceFlag   = flag.String("since", "", "Only process pages edited in this window, e.g. 24h or 7d, or since a date or RFC 3339 time")
+		sinceFile   = flag.String("since-file", "", "Watermark file; only pages edited since the previous successful run are synced")
+		checkpoint  = flag.String("checkpoint", "", "File recording sync progress, so an interrupted run resumes where it stopped")
+		timeout     = flag.Duration("timeout", 0, "Abort the whole run after this long, e.g. 30m (default: no limit)")
+		lookupTO    = flag.Duration("lookup-timeout", 0, "Give up on a single people lookup, retries included, after this long (default: no limit)")
+		overlap     = flag.Duration("since-overlap", 5*time.Minute, "How far before the previous run's start to look back with -since-file")
+		outputFlag  = flag.String("output", "", "Output format: csv (default) or json in export mode; text (default), json or csv in sync modes")
+		columnsFlag = flag.String("columns", "", "Comma-separated properties to export (default: all)")
+		multiSep    = flag.String("multi-sep", defaultMultiValueSep, "Separator joining multiple values in a CSV cell")
+		force       = flag.Bool("force", false, "Also recompute relations that are already set, updating those that differ")
+		retryConfl  = flag.Bool("retry-conflicts", false, "On a 409 conflict, re-read the page, merge its People relation and retry once")
+		nameMap     = flag.String("name-map", "", "File mapping name aliases to canonical names, one \"alias = Canonical\" per line")
+		outFlag     = flag.String("out", "", "Output directory in dump-json mode")
+		separators  = flag.String("separators", defaultSeparators, "Delimiters between names, separated by |, e.g. \", | and | & |;\"")
+		transforms  = flag.String("name-transforms", "", "Comma-separated rewrites of each name before matching: strip-honorifics, last-first, collapse-space")
+		personIcon  = flag.String("person-icon", "", "Emoji icon for people pages created by the sync, e.g. 👤")
+		verbose     = flag.Bool("verbose", false, "Also log every lookup and skipped page")
+		quiet       = flag.Bool("quiet", false, "Only log warnings and errors")
+		prewarm     = flag.Bool("prewarm", false, "List the whole people database once up front instead of looking up each name")
+		preview     = flag.Int("preview", 0, "Show how the first N pages would be parsed and matched, without writing anything")
+		timestamps  = flag.Bool("timestamps", false, "Add the page's _created_time and _last_edited_time columns to exports")
+		tzFlag      = flag.String("timezone", "", "Time zone to export timestamps in, e.g. Europe/Berlin or UTC (default: as Notion returns them)")
+		sortFlag    = flag.String("sort", "", "Sort exports by created_time or last_edited_time, optionally suffixed with :descending")
+		relTitles   = flag.Bool("relation-titles", false, "Export relation properties as the titles of the related pages instead of their IDs")
+		manifest    = flag.String("manifest", "", "Write a JSON manifest describing the run to this file, even if it fails")
+		reportHTML  = flag.String("report-html", "", "Write an HTML summary of the run to this file")
+		diffReport  = flag.String("diff-report", "", "Write the people added to and removed from each page's relation to this file")
+		diffFormat  = flag.String("diff-format", outputText, "Format of the -diff-report file: text or json")
+		numberFmt   = flag.Bool("number-format", false, "Format exported numbers per their Notion format and describe columns in JSON output")
+	)
 	var (
-		tokenFlag = flag.String("token", "", "Notion integration token (or set NOTION_TOKEN)")
-		fieldName = flag.String("field", defaultWhoPropName, "Property name to extract (default: who)")
+		sources      sourceList
+		defaultProps defaultPropList
 	)
+	flag.Var(&sources, "source", "Data source to sync as ID or ID:Field,Field (default fields: -field); repeat to sync several into the same people database")
+	flag.Var(&defaultProps, "default-prop", "Property to set on people pages the sync creates as Name=value:type, e.g. Source=sync:select; repeatable")
 	flag.Parse()
 
-	token := strings.TrimSpace(*tokenFlag)
-	if token == "" {
-		token = strings.TrimSpace(os.Getenv("NOTION_TOKEN"))
+	cfg := config{
+		token:          strings.TrimSpace(*tokenFlag),
+		notionVersion:  strings.TrimSpace(*versionFlag),
+		mode:           strings.TrimSpace(*modeFlag),
+		sourceParent:   strings.TrimSpace(*sourceKind),
+		peopleParent:   strings.TrimSpace(*peopleKind),
+		fields:         splitList(*fieldName),
+		relationField:  strings.TrimSpace(*relField),
+		titleField:     strings.TrimSpace(*titleFlag),
+		peopleTitle:    strings.TrimSpace(*peopleTitle),
+		file:           strings.TrimSpace(*fileFlag),
+		idColumn:       strings.TrimSpace(*idColumn),
+		types:          strings.TrimSpace(*typesFlag),
+		dryRun:         *dryRun,
+		concurrency:    *concurrency,
+		sinceFile:      strings.TrimSpace(*sinceFile),
+		checkpoint:     strings.TrimSpace(*checkpoint),
+		overlap:        *overlap,
+		timeout:        *timeout,
+		lookupTimeout:  *lookupTO,
+		output:         strings.TrimSpace(*outputFlag),
+		columns:        splitList(*columnsFlag),
+		multiSep:       *multiSep,
+		numberFormat:   *numberFmt,
+		reportHTML:     strings.TrimSpace(*reportHTML),
+		diffReport:     strings.TrimSpace(*diffReport),
+		diffFormat:     strings.TrimSpace(*diffFormat),
+		out:            strings.TrimSpace(*outFlag),
+		retryConflicts: *retryConfl,
+		force:          *force,
+		relationTitles: *relTitles,
+		preview:        *preview,
+		manifest:       strings.TrimSpace(*manifest),
+		timestamps:     *timestamps,
+		prewarm:        *prewarm,
+		personIcon:     strings.TrimSpace(*personIcon),
+		sources:        sources,
+		defaultProps:   defaultProps,
 	}
-	if token == "" {
-		fatal(errors.New("missing token: pass -token or set NOTION_TOKEN"))
+	for _, sep := range strings.Split(*separators, "|") {
+		if sep != "" {
+			cfg.separators = append(cfg.separators, sep)
+		}
 	}
-
-	srcField := strings.TrimSpace(*fieldName)
-	if srcField == "" {
-		fatal(errors.New("field name cannot be empty"))
+	if len(cfg.separators) == 0 {
+		return cfg, errors.New("separators cannot be empty")
+	}
+	var err error
+	if cfg.transforms, err = parseNameTransforms(splitList(*transforms)); err != nil {
+		return cfg, err
+	}
+	switch {
+	case *verbose && *quiet:
+		return cfg, errors.New("-verbose and -quiet cannot be combined")
+	case *verbose:
+		cfg.logLevel = levelDebug
+	case *quiet:
+		cfg.logLevel = levelWarn
+	default:
+		cfg.logLevel = levelInfo
+	}
+	if cfg.token == "" {
+		cfg.token = strings.TrimSpace(os.Getenv("NOTION_TOKEN"))
+	}
+	if cfg.token == "" {
+		return cfg, errors.New("missing token: pass -token or set NOTION_TOKEN")
+	}
+	if cfg.dataSource, err = resolveID("data-source", *dataSource, "NOTION_DATA_SOURCE_ID", NotionChroniclesDataSourceID); err != nil {
+		return cfg, err
+	}
+	if cfg.peopleDB, err = resolveID("people-db", *peopleDB, "NOTION_PEOPLE_DB_ID", NotionPeopleDatabaseID); err != nil {
+		return cfg, err
+	}
+	for flagName, kind := range map[string]string{"data-source-parent": cfg.sourceParent, "people-parent": cfg.peopleParent} {
+		switch kind {
+		case notion.ParentAuto, notion.ParentDataSource, notion.ParentDatabase:
+		default:
+			return cfg, fmt.Errorf("unknown -%s %q: expected auto, data_source or database", flagName, kind)
+		}
+	}
+	if _, err := time.Parse(time.DateOnly, cfg.notionVersion); err != nil {
+		return cfg, fmt.Errorf("invalid -notion-version %q: expected a date like %s", cfg.notionVersion, notion.NotionVersion)
+	}
+	if cfg.concurrency < 1 {
+		return cfg, errors.New("concurrency must be at least 1")
+	}
+	if cfg.timeout < 0 {
+		return cfg, errors.New("timeout cannot be negative")
+	}
+	if cfg.lookupTimeout < 0 {
+		return cfg, errors.New("lookup-timeout cannot be negative")
+	}
+	if cfg.overlap < 0 {
+		return cfg, errors.New("since-overlap cannot be negative")
+	}
+	if v := strings.TrimSpace(*sinceFlag); v != "" {
+		if cfg.sinceFile != "" {
+			return cfg, errors.New("-since and -since-file cannot be combined")
+		}
+		if cfg.since, err = parseSince(v, time.Now()); err != nil {
+			return cfg, err
+		}
+	}
+	if v := strings.TrimSpace(*tzFlag); v != "" {
+		if cfg.location, err = time.LoadLocation(v); err != nil {
+			return cfg, fmt.Errorf("invalid -timezone: %w", err)
+		}
+	}
+	if v := strings.TrimSpace(*sortFlag); v != "" {
+		ts, err := parseTimestampSort(v)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.sorts = []notion.Sort{ts}
+	}
+	if cfg.multiSep == "" {
+		return cfg, errors.New("multi-sep cannot be empty")
+	}
+	if cfg.preview < 0 {
+		return cfg, errors.New("preview cannot be negative")
+	}
+	if path := strings.TrimSpace(*nameMap); path != "" {
+		aliases, err := loadNameMap(path)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.aliases = newNameMap(aliases)
 	}
 
-	ctx := context.Background()
-	client := notion.NewClient(token)
+	switch cfg.mode {
+	case modeSync, modeCreatePeople, modeLinkRelations, modeCheck:
+		if len(cfg.fields) == 0 {
+			return cfg, errors.New("field name cannot be empty")
+		}
+		if cfg.relationField == "" {
+			return cfg, errors.New("relation field cannot be empty")
+		}
+		if cfg.output == "" {
+			cfg.output = outputText
+		}
+		if cfg.output != outputText && cfg.output != outputCSV && cfg.output != outputJSON {
+			return cfg, fmt.Errorf("unknown output format %q", cfg.output)
+		}
+		if cfg.diffFormat != outputText && cfg.diffFormat != outputJSON {
+			return cfg, fmt.Errorf("unknown diff format %q", cfg.diffFormat)
+		}
+		if len(cfg.sources) > 0 {
+			// Watermarks and checkpoints track a single data source.
+			switch {
+			case cfg.sinceFile != "" || cfg.checkpoint != "":
+				return cfg, errors.New("-source cannot be combined with -since-file or -checkpoint")
+			case cfg.preview > 0 || cfg.mode == modeCheck:
+				return cfg, errors.New("-source cannot be combined with -preview or check mode")
+			}
+		}
+	case modeImportCSV:
+		if cfg.file == "" {
+			return cfg, errors.New("missing CSV file: pass -file")
+		}
+		if cfg.idColumn == "" {
+			return cfg, errors.New("id column cannot be empty")
+		}
+	case modeDumpJSON:
+		if cfg.out == "" {
+			return cfg, errors.New("missing output directory: pass -out")
+		}
+	case modeSources:
+	case modeExport:
+		if cfg.output == "" {
+			cfg.output = outputCSV
+		}
+		if cfg.output != outputCSV && cfg.output != outputJSON {
+			return cfg, fmt.Errorf("unknown output format %q", cfg.output)
+		}
+	default:
+		return cfg, fmt.Errorf("unknown mode %q", cfg.mode)
+	}
+	return cfg, nil
+}
 
-	// Reduce payload to just the property we care about.
-	qp := url.Values{}
-	qp.Add("filter_properties[]", "Name")
-	qp.Add("filter_properties[]", srcField)
-	qp.Add("filter_properties[]", "People")
+func run(ctx context.Context, cfg config) error {
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
 
-	var cursor *string
-	for {
-		req := notion.QueryRequest{
-			PageSize:    notion.DefaultPageSize,
-			StartCursor: cursor,
-		}
+	client := notion.NewClient(cfg.token, notion.WithLookupTimeout(cfg.lookupTimeout), notion.WithNotionVersion(cfg.notionVersion))
+	rep := newRunReport(cfg)
 
-		var resp notion.QueryResponse
-		if err := client.Do(ctx, http.MethodPost, "/data_sources/"+NotionChroniclesDataSourceID+"/query", qp, req, &resp); err != nil {
-			fatal(err)
-		}
+	var err error
+	switch cfg.mode {
+	case modeImportCSV:
+		err = runImportCSV(ctx, client, cfg)
+	case modeSources:
+		err = runListSources(ctx, client)
+	default:
+		err = runOnSources(ctx, client, cfg, rep)
+	}
 
-		for _, pg := range resp.Results {
-			prop, ok := pg.Properties[srcField]
-			title, _ := pg.Properties["Name"]
-			fmt.Println(notion.ExtractString(title))
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("run timed out after %s: %w", cfg.timeout, err)
+	}
+	infof("API requests: %s\n", client.Stats())
 
-			if !ok {
-				fatal(fmt.Errorf("property %q not found on returned pages; check the exact column name in Notion", srcField))
-			}
+	rep.Finished = time.Now()
+	if cfg.reportHTML != "" {
+		if err != nil {
+			rep.Errors = append(rep.Errors, err.Error())
+		}
+		if werr := rep.writeHTML(cfg.reportHTML); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	if cfg.diffReport != "" {
+		if werr := writeDiffReport(cfg.diffReport, cfg.diffFormat, rep.Changes); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	if cfg.manifest != "" {
+		if werr := writeManifest(cfg.manifest, cfg, rep, err); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
 
-			// Check if People field is empty
-			peopleProp, peopleExists := pg.Properties["People"]
-			if peopleExists && len(peopleProp.Relation) > 0 {
-				// People field is not empty, skip updating
-				fmt.Println(".")
-				continue
-			}
+// runOnSources resolves the data sources to read and the people data source,
+// where a database given instead stands for its only data source, and runs
+// the modes that read them
+func runOnSources(ctx context.Context, client *notion.Client, cfg config, rep *runReport) error {
+	var err error
+	if len(cfg.sources) == 0 {
+		if cfg.dataSource, err = client.ResolveDataSource(ctx, cfg.dataSource, cfg.sourceParent); err != nil {
+			return fmt.Errorf("data source: %w", err)
+		}
+	}
+	for i, src := range cfg.sources {
+		if cfg.sources[i].dataSource, err = client.ResolveDataSource(ctx, src.dataSource, cfg.sourceParent); err != nil {
+			return fmt.Errorf("source %s: %w", src.dataSource, err)
+		}
+	}
 
-			who := notion.ExtractString(prop)
-
-			cleanedPersons := extractPersons(who)
-
-			// Create/update people pages and collect their IDs
-			var peoplePageIDs []string
-			for _, personName := range cleanedPersons {
-				if personName == "" {
-					continue
-				}
-
-				// Check if a page with this name already exists
-				existingPage, err := client.FindPageByTitle(ctx, NotionPeopleDatabaseID, personName)
-				if err != nil {
-					fatal(fmt.Errorf("failed to check for existing people page for %s: %w", personName, err))
-				}
-
-				var pageID string
-				if existingPage != nil {
-					// Page already exists, use its ID
-					pageID = existingPage.ID
-					fmt.Printf("Found existing page for %s: %s\n", personName, pageID)
-				} else {
-					// Create a new page in the people database
-					peopleProps := map[string]notion.PropertyValue{
-						"Name": {
-							Type: "title",
-							Title: []notion.RichText{
-								{
-									Type: "text",
-									Text: &notion.TextContent{Content: personName},
-								},
-							},
-						},
-					}
-
-					peoplePage, err := client.CreatePage(ctx, NotionPeopleDatabaseID, peopleProps)
-					if err != nil {
-						fatal(fmt.Errorf("failed to create people page for %s: %w", personName, err))
-					}
-					pageID = peoplePage.ID
-					fmt.Printf("Created new page for %s: %s\n", personName, pageID)
-				}
-				peoplePageIDs = append(peoplePageIDs, pageID)
-			}
+	switch cfg.mode {
+	case modeExport:
+		return runExport(ctx, client, cfg)
+	case modeDumpJSON:
+		return runDumpJSON(ctx, client, cfg)
+	}
 
-			// Update the People field with the extracted persons
-			if len(peoplePageIDs) == 0 {
-				continue
-			}
+	// People pages are queried and created in a data source.
+	if cfg.peopleDB, err = client.ResolveDataSource(ctx, cfg.peopleDB, cfg.peopleParent); err != nil {
+		return fmt.Errorf("people database: %w", err)
+	}
+	if cfg.mode == modeCheck {
+		return runCheck(ctx, client, cfg)
+	}
+	if cfg.peopleTitle, err = checkPeopleSchema(ctx, client, cfg); err != nil {
+		return err
+	}
+	switch {
+	case cfg.preview > 0:
+		return runPreview(ctx, client, cfg)
+	default:
+		return runSync(ctx, client, cfg, rep)
+	}
+}
 
-			relationRefs := make([]notion.RelationRef, 0, len(peoplePageIDs))
-			for _, pageID := range peoplePageIDs {
-				relationRefs = append(relationRefs, notion.RelationRef{ID: pageID})
-			}
+// resolveID returns the normalized ID given by the flag, else by the environment
+// variable, else the built-in default
+func resolveID(name, flagValue, envVar, def string) (string, error) {
+	id, source := strings.TrimSpace(flagValue), "-"+name
+	if id == "" {
+		id, source = strings.TrimSpace(os.Getenv(envVar)), envVar
+	}
+	if id == "" {
+		return def, nil
+	}
+	normalized, err := notion.NormalizeID(id)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+	return normalized, nil
+}
 
-			updateProps := map[string]notion.PropertyValue{
-				"People": {
-					Type:     "relation",
-					Relation: relationRefs,
-				},
-			}
+// parseTimestampSort parses "created_time" or "last_edited_time", optionally
+// suffixed with ":ascending" or ":descending"
+func parseTimestampSort(s string) (notion.Sort, error) {
+	ts, dir, _ := strings.Cut(s, ":")
+	if dir == "" {
+		dir = notion.Ascending
+	}
+	sort := notion.TimestampSort(ts, dir)
+	if err := sort.Validate(); err != nil {
+		return notion.Sort{}, fmt.Errorf("invalid -sort %q: %w", s, err)
+	}
+	return sort, nil
+}
 
-			if err := client.UpdatePage(ctx, pg.ID, updateProps); err != nil {
-				fatal(fmt.Errorf("failed to update page %s: %w", pg.ID, err))
+// parseSince parses -since: a duration before now such as "90m" or, in days,
+// "7d", or a point in time given as a date or RFC 3339 timestamp
+func parseSince(v string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			if n <= 0 {
+				return time.Time{}, fmt.Errorf("invalid -since %q: must be positive", v)
 			}
-
+			return now.AddDate(0, 0, -n), nil
 		}
-
-		if !resp.HasMore || resp.NextCursor == nil || *resp.NextCursor == "" {
-			break
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("invalid -since %q: must be positive", v)
 		}
-		cursor = resp.NextCursor
+		return now.Add(-d), nil
 	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid -since %q: expected a duration like 24h or 7d, a date or an RFC 3339 time", v)
 }
 
-func extractPersons(who string) []string {
-	persons := strings.Split(who, ", ")
-	var cleanedPersons []string
-	for _, p := range persons {
-		p = strings.TrimSpace(p)
-		cleanedPersons = append(cleanedPersons, p)
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
 	}
-	return cleanedPersons
+	return out
 }
 
 func fatal(err error) {
 	fmt.Fprintln(os.Stderr, "error:", err)
+	if hint := errorHint(err); hint != "" {
+		fmt.Fprintln(os.Stderr, "hint:", hint)
+	}
 	os.Exit(1)
 }
+
+// errorHint turns the common setup failures into actionable guidance
+func errorHint(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "raise -timeout, or pass -checkpoint so the next run resumes where this one stopped"
+	}
+	var apiErr *notion.APIError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized:
+		return "check your token: it is missing, mistyped or was revoked in https://www.notion.so/profile/integrations"
+	case http.StatusForbidden:
+		return fmt.Sprintf("share the %s with your integration in Notion → ••• → Connections", describeObject(apiErr.Path))
+	default:
+		return ""
+	}
+}
+
+// describeObject names the object an API path refers to, e.g. "data source <id>"
+func describeObject(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 {
+		return "database/page"
+	}
+	kind := map[string]string{
+		"data_sources": "data source",
+		"databases":    "database",
+		"pages":        "page",
+		"blocks":       "block",
+	}[parts[0]]
+	if kind == "" {
+		return "database/page"
+	}
+	return kind + " " + parts[1]
+}
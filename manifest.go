@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"notion-tools/internal/notion"
+)
+
+// toolVersion is reported in run manifests
+const toolVersion = "1.0"
+
+// runManifest is a provenance record describing a single run
+type runManifest struct {
+	Tool             string            `json:"tool"`
+	Version          string            `json:"version"`
+	NotionVersion    string            `json:"notion_version"`
+	Mode             string            `json:"mode"`
+	DataSourceID     string            `json:"data_source_id"`
+	PeopleDatabaseID string            `json:"people_database_id"`
+	Flags            map[string]string `json:"flags"`
+	ConfigHash       string            `json:"config_hash"`
+	Started          time.Time         `json:"started"`
+	Finished         time.Time         `json:"finished"`
+	Counts           map[string]int    `json:"counts"`
+	Error            string            `json:"error,omitempty"`
+}
+
+// writeManifest records the run described by rep to path. runErr, if any, is
+// included so partial failures are still documented.
+func writeManifest(path string, cfg config, rep *runReport, runErr error) error {
+	m := runManifest{
+		Tool:             "notion-tools",
+		Version:          toolVersion,
+		NotionVersion:    notion.NotionVersion,
+		Mode:             cfg.mode,
+		DataSourceID:     cfg.dataSource,
+		PeopleDatabaseID: cfg.peopleDB,
+		Flags:            map[string]string{},
+		ConfigHash:       configHash(),
+		Started:          rep.Started,
+		Finished:         rep.Finished,
+		Counts: map[string]int{
+			"pages_processed": len(rep.Pages),
+			"pages_updated":   rep.Count(actionUpdated),
+			"pages_skipped":   rep.Count(actionSkipped),
+			"people_created":  len(rep.Created),
+			"people_reused":   len(rep.Reused),
+		},
+	}
+	flag.Visit(func(f *flag.Flag) {
+		m.Flags[f.Name] = redactFlag(f)
+	})
+	if runErr != nil {
+		m.Error = runErr.Error()
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+	if err := writeFileAtomic(path, append(b, '\n')); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+// configHash fingerprints the effective configuration: every flag with its
+// value, including defaults, excluding the token.
+func configHash() string {
+	var lines []string
+	flag.VisitAll(func(f *flag.Flag) {
+		lines = append(lines, f.Name+"="+redactFlag(f))
+	})
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// redactFlag returns the flag value, hiding secrets
+func redactFlag(f *flag.Flag) string {
+	if f.Name == "token" {
+		return "<redacted>"
+	}
+	return f.Value.String()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"notion-tools/internal/notion"
+)
+
+// syncSource is a data source to sync and the fields naming its persons
+type syncSource struct {
+	dataSource string
+	fields     []string
+}
+
+// sourceList collects repeated -source flags of the form "ID" or "ID:Field,Field"
+type sourceList []syncSource
+
+func (l *sourceList) String() string {
+	parts := make([]string, len(*l))
+	for i, src := range *l {
+		parts[i] = src.dataSource
+		if len(src.fields) > 0 {
+			parts[i] += ":" + strings.Join(src.fields, ",")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (l *sourceList) Set(v string) error {
+	id, fields, _ := strings.Cut(v, ":")
+	id, err := notion.NormalizeID(strings.TrimSpace(id))
+	if err != nil {
+		return err
+	}
+	*l = append(*l, syncSource{dataSource: id, fields: splitList(fields)})
+	return nil
+}
+
+// runSources syncs each of cfg.sources in turn into the people database,
+// sharing one people resolver so a person is looked up and created once for
+// all of them, and one encoder so the records form a single output. It stops
+// at the first source that fails.
+func runSources(ctx context.Context, client notion.PageAPI, cfg config, rep *runReport) (err error) {
+	people := newPeopleResolver(client, cfg)
+	var enc Encoder
+	if cfg.output != outputText {
+		if enc, err = newSyncEncoder(cfg, os.Stdout); err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := enc.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}()
+	}
+	for i, src := range cfg.sources {
+		scfg := cfg
+		scfg.dataSource = src.dataSource
+		if len(src.fields) > 0 {
+			scfg.fields = src.fields
+		}
+		// Listing the people database once serves every source.
+		scfg.prewarm = cfg.prewarm && i == 0
+
+		s := NewSyncer(client, scfg)
+		s.people = people
+		s.enc = enc
+		sr, err := s.Run(ctx)
+		rep.merge(sr)
+		infof("%s: %d pages processed: %d updated, %d skipped; %d people created, %d reused\n",
+			src.dataSource, len(sr.Pages), sr.Count(actionUpdated), sr.Count(actionSkipped), len(sr.Created), len(sr.Reused))
+		if err != nil {
+			return fmt.Errorf("source %s: %w", src.dataSource, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// loadNameMap reads a name map file with one "alias = Canonical Name" mapping
+// per line. Blank lines and lines starting with # are ignored. Every problem
+// found, including an alias mapped more than once, is reported together.
+func loadNameMap(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open name map: %w", err)
+	}
+	defer f.Close()
+	return parseNameMap(f, path)
+}
+
+func parseNameMap(r io.Reader, name string) (map[string]string, error) {
+	aliases := map[string]string{}
+	definedAt := map[string]int{}
+
+	var problems []string
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		alias, canonical, ok := strings.Cut(text, "=")
+		alias, canonical = strings.TrimSpace(alias), strings.TrimSpace(canonical)
+		if !ok || alias == "" || canonical == "" {
+			problems = append(problems, fmt.Sprintf("%s:%d: expected \"alias = Canonical Name\"", name, line))
+			continue
+		}
+		if prev, dup := definedAt[alias]; dup {
+			problems = append(problems, fmt.Sprintf("%s:%d: %q is already mapped on line %d", name, line, alias, prev))
+			continue
+		}
+		definedAt[alias] = line
+		aliases[alias] = canonical
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read name map: %w", err)
+	}
+	if len(problems) > 0 {
+		return nil, errors.New("invalid name map:\n  " + strings.Join(problems, "\n  "))
+	}
+	return aliases, nil
+}
+
+// nameMap applies the aliases of a name map, reporting each alias the first
+// time it is applied. A nil nameMap maps nothing.
+type nameMap struct {
+	aliases map[string]string
+
+	mu       sync.Mutex
+	reported map[string]bool
+}
+
+func newNameMap(aliases map[string]string) *nameMap {
+	return &nameMap{aliases: aliases, reported: map[string]bool{}}
+}
+
+// canonical maps an extracted name to its canonical form. Unmapped names pass
+// through unchanged.
+func (m *nameMap) canonical(name string) string {
+	if m == nil {
+		return name
+	}
+	canonical, ok := m.aliases[name]
+	if !ok {
+		return name
+	}
+	m.mu.Lock()
+	first := !m.reported[name]
+	m.reported[name] = true
+	m.mu.Unlock()
+	if first {
+		infof("Alias %s → %s\n", name, canonical)
+	}
+	return canonical
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestParseNameMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		want     map[string]string
+		wantErrs []string // problems the error must report, each with its line
+	}{
+		{
+			name: "mappings, comments and blank lines",
+			file: "# aliases\nBob = Robert Smith\n\n  Bobby=Robert Smith  \nRobert J. = Robert Smith\n",
+			want: map[string]string{"Bob": "Robert Smith", "Bobby": "Robert Smith", "Robert J.": "Robert Smith"},
+		},
+		{
+			name:     "duplicate alias",
+			file:     "Bob = Robert Smith\nAl = Alice\nBob = Bob Jones\n",
+			wantErrs: []string{`names.txt:3: "Bob" is already mapped on line 1`},
+		},
+		{
+			name: "malformed lines",
+			file: "Bob Robert Smith\n = Alice\nAl =\nCarol = Caroline\n",
+			wantErrs: []string{
+				`names.txt:1: expected "alias = Canonical Name"`,
+				`names.txt:2: expected "alias = Canonical Name"`,
+				`names.txt:3: expected "alias = Canonical Name"`,
+			},
+		},
+		{
+			name:     "every problem reported together",
+			file:     "Bob = Robert\nnot a mapping\nBob = Bobby\n",
+			wantErrs: []string{"names.txt:2: expected", `names.txt:3: "Bob" is already mapped on line 1`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNameMap(strings.NewReader(tt.file), "names.txt")
+			if len(tt.wantErrs) > 0 {
+				if err == nil {
+					t.Fatalf("parsed %v, want an error", got)
+				}
+				for _, want := range tt.wantErrs {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error %q does not report %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("parsed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameMapCanonical(t *testing.T) {
+	m := newNameMap(map[string]string{"Bob": "Robert Smith", "Bobby": "Robert Smith"})
+	tests := []struct{ in, want string }{
+		{"Bob", "Robert Smith"},
+		{"Bobby", "Robert Smith"},
+		{"Robert Smith", "Robert Smith"},
+		{"Alice", "Alice"},
+		{"bob", "bob"}, // aliases match exactly
+	}
+	for _, tt := range tests {
+		if got := m.canonical(tt.in); got != tt.want {
+			t.Errorf("canonical(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	var none *nameMap
+	if got := none.canonical("Bob"); got != "Bob" {
+		t.Errorf("canonical without a name map = %q, want Bob unchanged", got)
+	}
+}
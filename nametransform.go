@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"notion-tools/internal/notion"
+)
+
+// nameTransform rewrites a single extracted name before it is trimmed and matched
+type nameTransform func(string) string
+
+// nameTransforms are the transforms -name-transforms can select
+var nameTransforms = map[string]nameTransform{
+	"strip-honorifics": stripHonorifics,
+	"last-first":       reorderLastFirst,
+	"collapse-space":   notion.NormalizeTitle,
+}
+
+// parseNameTransforms looks up the named transforms, keeping their order
+func parseNameTransforms(names []string) ([]nameTransform, error) {
+	var out []nameTransform
+	for _, name := range names {
+		t, ok := nameTransforms[name]
+		if !ok {
+			known := make([]string, 0, len(nameTransforms))
+			for k := range nameTransforms {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown name transform %q: expected %s", name, strings.Join(known, ", "))
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// honorifics are dropped from the front of names by stripHonorifics,
+// compared case-insensitively and with or without a trailing period
+var honorifics = map[string]bool{
+	"dr": true, "mr": true, "mrs": true, "ms": true, "mx": true, "miss": true,
+	"prof": true, "sir": true, "dame": true, "rev": true,
+}
+
+// stripHonorifics turns "Dr. Alice Smith" into "Alice Smith". A name that is
+// nothing but honorifics is kept as it is.
+func stripHonorifics(name string) string {
+	words := strings.Fields(name)
+	i := 0
+	for i < len(words)-1 && honorifics[strings.ToLower(strings.TrimSuffix(words[i], "."))] {
+		i++
+	}
+	if i == 0 {
+		return name
+	}
+	return strings.Join(words[i:], " ")
+}
+
+// reorderLastFirst turns "Smith, Alice" into "Alice Smith". Names with no
+// comma or several are left alone. The comma must survive splitting, so it
+// needs -separators that don't include ",".
+func reorderLastFirst(name string) string {
+	last, first, ok := strings.Cut(name, ",")
+	if !ok || strings.Contains(first, ",") {
+		return name
+	}
+	last, first = strings.TrimSpace(last), strings.TrimSpace(first)
+	if last == "" || first == "" {
+		return name
+	}
+	return first + " " + last
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"notion-tools/internal/notion"
+)
+
+// errStopPaging ends a QueryEach loop early without reporting an error
+var errStopPaging = errors.New("stop paging")
+
+// runPreview shows how the first cfg.preview pages' source values would be
+// parsed and matched against the people database, without writing anything.
+func runPreview(ctx context.Context, client notion.PageAPI, cfg config) error {
+	schema, err := client.GetDataSource(ctx, cfg.dataSource)
+	if err != nil {
+		return fmt.Errorf("failed to read data source schema: %w", err)
+	}
+	infof("Previewing data source %s\n", schema.Info())
+	for _, field := range cfg.fields {
+		if err := schema.CheckProperty(field); err != nil {
+			return fmt.Errorf("%w; set -field to the exact column names", err)
+		}
+	}
+	if cfg.titleField, err = titleField(schema, cfg.dataSource, cfg.titleField, "title-field"); err != nil {
+		return err
+	}
+	qp := notion.FilterProperties(append([]string{cfg.titleField}, cfg.fields...)...)
+
+	people := notion.NewPeopleResolver(client, cfg.peopleDB, cfg.peopleTitle)
+	// Fetch no more pages than are shown, and stop once the last one is.
+	req := notion.QueryRequest{PageSize: min(cfg.preview, notion.MaxPageSize)}
+	seen := 0
+	err = client.QueryEach(ctx, cfg.dataSource, req, qp, func(pg notion.Page) error {
+		seen++
+
+		names, err := pagePersons(cfg, pg)
+		if err != nil {
+			return err
+		}
+		raw := make([]string, len(cfg.fields))
+		for i, field := range cfg.fields {
+			raw[i] = fmt.Sprintf("%q", notion.ExtractString(pg.Properties[field]))
+		}
+
+		fmt.Printf("%s\n  %s → [%s]\n", notion.ExtractString(pg.Properties[cfg.titleField]), strings.Join(raw, " + "), strings.Join(names, ", "))
+		for _, name := range names {
+			pageID, err := people.Lookup(ctx, name)
+			if err != nil {
+				return fmt.Errorf("failed to check for existing people page for %s: %w", name, err)
+			}
+			if pageID != "" {
+				fmt.Printf("    %s: existing page %s\n", name, pageID)
+			} else {
+				fmt.Printf("    %s: would create\n", name)
+			}
+		}
+		if seen == cfg.preview {
+			return errStopPaging
+		}
+		return nil
+	})
+	if errors.Is(err, errStopPaging) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"notion-tools/internal/notion"
+)
+
+// runReport collects what a run did so it can be rendered afterwards
+type runReport struct {
+	Started  time.Time
+	Finished time.Time
+	Params   []reportParam
+	Errors   []string
+	SyncReport
+}
+
+// SyncReport records what a sync did: the outcome of every page and the
+// people pages it created and reused, and how relations changed
+type SyncReport struct {
+	Pages   []PageResult
+	Created []string
+	Reused  []string
+	Changes []RelationChange
+}
+
+// reportParam is a single run parameter shown in the report
+type reportParam struct {
+	Name  string
+	Value string
+}
+
+// PageResult is the outcome of processing a single source page
+type PageResult struct {
+	ID     string
+	Title  string
+	URL    string
+	Action string
+	People []string
+}
+
+func newRunReport(cfg config) *runReport {
+	dataSource := cfg.dataSource
+	if len(cfg.sources) > 0 {
+		l := sourceList(cfg.sources)
+		dataSource = l.String()
+	}
+	return &runReport{
+		Started: time.Now(),
+		Params: []reportParam{
+			{"Mode", cfg.mode},
+			{"Field", strings.Join(cfg.fields, ", ")},
+			{"Data source", dataSource},
+			{"People database", cfg.peopleDB},
+			{"Dry run", fmt.Sprint(cfg.dryRun)},
+		},
+	}
+}
+
+// merge adds the outcome of another sync into the same people database. A
+// person is listed once, as created if either sync created it.
+func (r *SyncReport) merge(o SyncReport) {
+	r.Pages = append(r.Pages, o.Pages...)
+	r.Changes = append(r.Changes, o.Changes...)
+	r.Created = append(r.Created, o.Created...)
+	for _, name := range o.Reused {
+		if !slices.Contains(r.Created, name) && !slices.Contains(r.Reused, name) {
+			r.Reused = append(r.Reused, name)
+		}
+	}
+}
+
+// Count returns the number of pages with the given action
+func (r SyncReport) Count(action string) int {
+	n := 0
+	for _, p := range r.Pages {
+		if p.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// pageURL returns the page's Notion URL, deriving it from the ID when the API omitted it
+func pageURL(pg notion.Page) string {
+	if pg.URL != "" {
+		return pg.URL
+	}
+	return "https://www.notion.so/" + strings.ReplaceAll(pg.ID, "-", "")
+}
+
+// writeHTML renders the report as a self-contained HTML file
+func (r *runReport) writeHTML(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create HTML report: %w", err)
+	}
+
+	data := struct {
+		*runReport
+		Updated, Skipped int
+	}{r, r.Count(actionUpdated), r.Count(actionSkipped)}
+
+	if err := reportTemplate.Execute(f, data); err != nil {
+		f.Close()
+		return fmt.Errorf("render HTML report: %w", err)
+	}
+	return f.Close()
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Notion tools run report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #37352f; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #e3e2e0; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f7f6f3; }
+.errors li { color: #d44c47; }
+</style>
+</head>
+<body>
+<h1>Run report</h1>
+<p>Started {{.Started.Format "2006-01-02 15:04:05 MST"}}, finished {{.Finished.Format "2006-01-02 15:04:05 MST"}}.</p>
+
+<h2>Parameters</h2>
+<table>
+{{range .Params}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Pages processed</th><td>{{len .Pages}}</td></tr>
+<tr><th>Pages updated</th><td>{{.Updated}}</td></tr>
+<tr><th>Pages skipped</th><td>{{.Skipped}}</td></tr>
+<tr><th>People created</th><td>{{len .Created}}</td></tr>
+<tr><th>People reused</th><td>{{len .Reused}}</td></tr>
+<tr><th>Errors</th><td>{{len .Errors}}</td></tr>
+</table>
+
+{{if .Errors}}<h2>Errors</h2>
+<ul class="errors">
+{{range .Errors}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}
+<h2>Pages</h2>
+<table>
+<tr><th>Page</th><th>Action</th><th>People</th></tr>
+{{range .Pages}}<tr><td><a href="{{.URL}}">{{if .Title}}{{.Title}}{{else}}{{.ID}}{{end}}</a></td><td>{{.Action}}</td><td>{{range $i, $p := .People}}{{if $i}}, {{end}}{{$p}}{{end}}</td></tr>
+{{end}}</table>
+
+{{if .Created}}<h2>People created</h2>
+<ul>
+{{range .Created}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}
+{{if .Reused}}<h2>People reused</h2>
+<ul>
+{{range .Reused}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"notion-tools/internal/notion"
+)
+
+// runListSources prints the ID and title of every data source shared with the integration
+func runListSources(ctx context.Context, client *notion.Client) error {
+	results, err := client.Search(ctx, "", notion.ObjectFilter(notion.SearchDataSources))
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		fmt.Printf("%s\t%s\n", r.ID(), r.Title())
+	}
+	return nil
+}
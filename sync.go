@@ -0,0 +1,666 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"notion-tools/internal/notion"
+)
+
+// Actions recorded for each processed page
+const (
+	actionUpdated  = "updated"
+	actionSkipped  = "skipped"
+	actionNoPeople = "no people"
+	actionResolved = "people resolved"
+	actionPlanned  = "would update"
+	actionSame     = "unchanged"
+)
+
+// Columns of the records emitted with -output json or csv
+const (
+	syncNameColumn    = "Name"
+	syncPersonsColumn = "persons"
+)
+
+// runSync runs a Syncer and prints a summary of what it did. In create-people mode
+// only the people pages are created; in link-relations mode existing people pages
+// are linked and none are created. With -dry-run the pages are read as usual but
+// nothing is created or updated, and the plan is printed instead.
+//
+// With -source the listed data sources are synced one after the other, and the
+// summary covers all of them.
+func runSync(ctx context.Context, client notion.PageAPI, cfg config, rep *runReport) error {
+	var err error
+	if len(cfg.sources) > 0 {
+		err = runSources(ctx, client, cfg, rep)
+	} else {
+		rep.SyncReport, err = NewSyncer(client, cfg).Run(ctx)
+	}
+	sr := rep.SyncReport
+	infof("%d pages processed: %d updated, %d skipped; %d people created, %d reused\n",
+		len(sr.Pages), sr.Count(actionUpdated), sr.Count(actionSkipped), len(sr.Created), len(sr.Reused))
+	return err
+}
+
+// Syncer links the persons named in the source field of a data source's pages
+// to pages in the people data source, as configured by cfg
+type Syncer struct {
+	client notion.PageAPI
+	cfg    config
+	people *notion.PeopleResolver
+	enc    Encoder
+
+	// Out receives the extracted records with -output json or csv
+	Out io.Writer
+
+	mu     sync.Mutex
+	report SyncReport
+	seen   map[string]bool
+}
+
+// NewSyncer returns a Syncer using client, usually a *notion.Client, and
+// writing records to stdout
+func NewSyncer(client notion.PageAPI, cfg config) *Syncer {
+	return &Syncer{
+		client: client,
+		cfg:    cfg,
+		people: newPeopleResolver(client, cfg),
+		Out:    os.Stdout,
+		seen:   map[string]bool{},
+	}
+}
+
+// newPeopleResolver returns the resolver for cfg.peopleDB, giving created
+// pages the configured icon and default properties
+func newPeopleResolver(client notion.PageAPI, cfg config) *notion.PeopleResolver {
+	people := notion.NewPeopleResolver(client, cfg.peopleDB, cfg.peopleTitle)
+	if cfg.personIcon != "" {
+		people.Icon = notion.EmojiIcon(cfg.personIcon)
+	}
+	people.Properties = cfg.defaultProps.properties()
+	return people
+}
+
+// Run syncs every page and reports what was done. The report covers the pages
+// processed before a failure too.
+func (s *Syncer) Run(ctx context.Context) (SyncReport, error) {
+	err := s.run(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.report, err
+}
+
+func (s *Syncer) run(ctx context.Context) error {
+	cfg := s.cfg
+	started := time.Now()
+
+	var req notion.QueryRequest
+	if cfg.sinceFile != "" {
+		prev, err := readWatermark(cfg.sinceFile)
+		if err != nil {
+			return err
+		}
+		if !prev.IsZero() {
+			// Look back past the previous start so pages edited during that run are not missed.
+			req.Filter = notion.LastEditedOnOrAfter(prev.Add(-cfg.overlap))
+		}
+	}
+	if !cfg.since.IsZero() {
+		// Most recently edited first, so the pages that changed last are synced first.
+		req.Filter = notion.LastEditedOnOrAfter(cfg.since)
+		req.Sorts = []notion.Sort{notion.TimestampSort("last_edited_time", notion.Descending)}
+	}
+
+	title, err := checkSchema(ctx, s.client, cfg)
+	if err != nil {
+		return err
+	}
+	cfg.titleField, s.cfg.titleField = title, title
+
+	if cfg.prewarm {
+		if err := s.people.Prewarm(ctx); err != nil {
+			return fmt.Errorf("failed to list people database: %w", err)
+		}
+	}
+
+	// Reduce payload to just the properties we care about.
+	qp := notion.FilterProperties(append([]string{cfg.titleField, cfg.relationField}, cfg.fields...)...)
+
+	// An encoder set beforehand is shared with other syncers and closed by its owner.
+	owned := s.enc == nil && cfg.output != outputText
+	if owned {
+		if s.enc, err = newSyncEncoder(cfg, s.Out); err != nil {
+			return err
+		}
+	}
+
+	err = s.syncPages(ctx, req, qp)
+	if owned {
+		if cerr := s.enc.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if cfg.sinceFile != "" && !cfg.dryRun {
+		return writeWatermark(cfg.sinceFile, started)
+	}
+	return nil
+}
+
+// newSyncEncoder returns an encoder for the records of cfg.output, safe for
+// concurrent use, with the header already written
+func newSyncEncoder(cfg config, w io.Writer) (Encoder, error) {
+	inner, err := newEncoder(cfg.output, w, cfg.multiSep)
+	if err != nil {
+		return nil, err
+	}
+	enc := &lockedEncoder{Encoder: inner}
+	if err := enc.WriteHeader([]string{exportIDColumn, syncNameColumn, syncPersonsColumn}); err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+// addPage records the outcome of a page
+func (s *Syncer) addPage(pg notion.Page, title, action string, people []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Pages = append(s.report.Pages, PageResult{
+		ID:     pg.ID,
+		Title:  title,
+		URL:    pageURL(pg),
+		Action: action,
+		People: people,
+	})
+}
+
+// addChange records a relation change for the diff report
+func (s *Syncer) addChange(c RelationChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Changes = append(s.report.Changes, c)
+}
+
+// personCreated records a people page created by the run
+func (s *Syncer) personCreated(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Created = append(s.report.Created, name)
+}
+
+// personReused records an existing person page, once per name
+func (s *Syncer) personReused(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[name] {
+		return
+	}
+	s.seen[name] = true
+	s.report.Reused = append(s.report.Reused, name)
+}
+
+// syncPages runs syncPage for every queried page on up to cfg.concurrency
+// workers. Each page is handled by a single worker, and the people resolver
+// makes sure concurrent pages naming the same person share one page. The
+// first error cancels the remaining work and is returned.
+//
+// With cfg.checkpoint the run resumes where the checkpoint left off, skips the
+// pages it lists as done, and records progress after every page batch; the
+// checkpoint is removed once all pages are synced.
+func (s *Syncer) syncPages(ctx context.Context, req notion.QueryRequest, qp url.Values) error {
+	cfg := s.cfg
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var cp *checkpoint
+	if cfg.checkpoint != "" && !cfg.dryRun {
+		var err error
+		if cp, err = loadCheckpoint(cfg.checkpoint, cfg.dataSource); err != nil {
+			return err
+		}
+		if req.StartCursor = cp.startCursor(); req.StartCursor != nil {
+			infof("Resuming from checkpoint %s\n", cfg.checkpoint)
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, cfg.concurrency)
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	err := s.client.QueryBatches(ctx, cfg.dataSource, req, qp, func(resp *notion.QueryResponse) error {
+		for _, pg := range resp.Results {
+			if cp != nil && cp.isDone(pg.ID) {
+				continue
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				if err := s.syncPage(ctx, pg); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+					return
+				}
+				if cp != nil {
+					cp.markDone(pg.ID)
+				}
+			}()
+		}
+		if cp == nil {
+			return nil
+		}
+		// Only move the checkpoint past a batch once all of its pages are synced.
+		wg.Wait()
+		if failed() {
+			return ctx.Err()
+		}
+		return cp.advance(resp.Cursor())
+	})
+	wg.Wait()
+
+	// A worker's error explains why the query was cancelled, so it takes precedence.
+	if firstErr != nil {
+		err = firstErr
+	}
+	if cp != nil {
+		if err != nil {
+			if serr := cp.save(); serr != nil {
+				return fmt.Errorf("%w (and %v)", err, serr)
+			}
+			return err
+		}
+		return cp.clear()
+	}
+	return err
+}
+
+// lockedEncoder serializes records written by concurrent workers
+type lockedEncoder struct {
+	mu sync.Mutex
+	Encoder
+}
+
+func (e *lockedEncoder) WriteRecord(rec map[string][]string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.Encoder.WriteRecord(rec)
+}
+
+// checkSchema verifies, before any page is touched, that the data source has
+// the source fields and the relation property, and names any that are
+// missing. It returns the title property.
+func checkSchema(ctx context.Context, client notion.PageAPI, cfg config) (string, error) {
+	schema, err := client.GetDataSource(ctx, cfg.dataSource)
+	if err != nil {
+		return "", fmt.Errorf("failed to read data source schema: %w", err)
+	}
+	infof("Syncing data source %s\n", schema.Info())
+	for _, field := range cfg.fields {
+		if err := schema.CheckProperty(field); err != nil {
+			return "", fmt.Errorf("%w; set -field to the exact column names", err)
+		}
+	}
+	if err := schema.CheckProperty(cfg.relationField, "relation"); err != nil {
+		return "", fmt.Errorf("%w; set -relation-field to the relation column", err)
+	}
+	return titleField(schema, cfg.dataSource, cfg.titleField, "title-field")
+}
+
+// checkPeopleSchema verifies, before any person is looked up or created, that
+// the people data source has the default properties and that none of them is
+// its title. It returns the people data source's title property.
+func checkPeopleSchema(ctx context.Context, client notion.PageAPI, cfg config) (string, error) {
+	people, err := client.GetDataSource(ctx, cfg.peopleDB)
+	if err != nil {
+		return "", fmt.Errorf("failed to read people data source schema: %w", err)
+	}
+	title, err := titleField(people, cfg.peopleDB, cfg.peopleTitle, "people-title-field")
+	if err != nil {
+		return "", err
+	}
+	if err := checkDefaultProps(people, title, cfg.defaultProps); err != nil {
+		return "", err
+	}
+	return title, nil
+}
+
+// titleField returns field after checking it is the title property of schema,
+// or else the title property found in schema, falling back to
+// defaultTitleField. flagName is the flag that sets field.
+func titleField(schema *notion.Schema, dataSource, field, flagName string) (string, error) {
+	if field != "" {
+		if err := schema.CheckProperty(field, "title"); err != nil {
+			return "", fmt.Errorf("%w; set -%s to the title column", err, flagName)
+		}
+		return field, nil
+	}
+	if name, ok := schema.TitleProperty(); ok {
+		debugf("Using title property %q of %s\n", name, dataSource)
+		return name, nil
+	}
+	warnf("No title property in data source %s, assuming %q\n", dataSource, defaultTitleField)
+	return defaultTitleField, nil
+}
+
+// syncPage resolves the persons of a single page and sets its relation.
+// With an encoder, the page's ID, title and persons are written to it.
+func (s *Syncer) syncPage(ctx context.Context, pg notion.Page) error {
+	cfg := s.cfg
+
+	title := notion.ExtractString(pg.Properties[cfg.titleField])
+	infof("%s\n", title)
+
+	persons, err := pagePersons(cfg, pg)
+	if err != nil {
+		return err
+	}
+	if s.enc != nil {
+		err := s.enc.WriteRecord(map[string][]string{
+			exportIDColumn:    {pg.ID},
+			syncNameColumn:    {title},
+			syncPersonsColumn: persons,
+		})
+		if err != nil {
+			return fmt.Errorf("write record for page %s: %w", pg.ID, err)
+		}
+	}
+
+	// Check if the relation is empty; -force recomputes relations that are already set
+	relation := pg.Properties[cfg.relationField]
+	if relation.HasMore && relation.ID != "" && cfg.force {
+		// Only the first related pages were inlined; compare against all of them.
+		full, err := s.client.GetFullProperty(ctx, pg.ID, relation.ID)
+		if err != nil {
+			return err
+		}
+		relation = full
+	}
+	current := notion.ExtractStrings(relation)
+	if len(current) > 0 && !cfg.force {
+		// Relation is not empty, skip updating
+		debugf("%s already set, skipped\n", cfg.relationField)
+		s.addPage(pg, title, actionSkipped, nil)
+		return nil
+	}
+
+	// Create/update people pages. IDs are kept by source position, so the
+	// relation lists people in the order the source names them however the
+	// lookups are served.
+	resolved := make([]string, len(persons))
+	var names, missing []string
+	for i, personName := range persons {
+		pageID, err := s.resolvePerson(ctx, personName)
+		if err != nil {
+			return err
+		}
+		if pageID == "" {
+			// Only in dry-run: the page would be created.
+			missing = append(missing, personName)
+		}
+		resolved[i] = pageID
+		names = append(names, personName)
+	}
+	peoplePageIDs := relationIDs(resolved)
+
+	if cfg.dryRun {
+		planSync(cfg, peoplePageIDs, missing)
+		if cfg.mode != modeCreatePeople && (len(missing) > 0 || !sameIDs(current, peoplePageIDs)) {
+			c := newRelationChange(pg.ID, title, current, peoplePageIDs)
+			c.New = missing
+			s.addChange(c)
+		}
+		s.addPage(pg, title, actionPlanned, names)
+		return nil
+	}
+
+	if cfg.mode == modeCreatePeople {
+		// People pages exist now; relations are set by a later link-relations pass.
+		s.addPage(pg, title, actionResolved, names)
+		return nil
+	}
+
+	// Update the relation with the extracted persons
+	if len(peoplePageIDs) == 0 {
+		s.addPage(pg, title, actionNoPeople, nil)
+		return nil
+	}
+	if sameIDs(current, peoplePageIDs) {
+		// Writing the same relation would only bump last_edited_time.
+		debugf("%s already up to date\n", cfg.relationField)
+		s.addPage(pg, title, actionSame, names)
+		return nil
+	}
+
+	updateProps := map[string]notion.PropertyValue{
+		cfg.relationField: notion.RelationValue(peoplePageIDs...),
+	}
+
+	before, after := current, peoplePageIDs
+	err = s.client.UpdatePage(ctx, pg.ID, updateProps)
+	if err != nil && cfg.retryConflicts && notion.HasStatus(err, http.StatusConflict) {
+		warnf("Conflict updating %s, re-reading and retrying once\n", pg.ID)
+		before, after, err = reapplyRelation(ctx, s.client, pg.ID, cfg.relationField, peoplePageIDs)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update page %s: %w", pg.ID, err)
+	}
+	s.addChange(newRelationChange(pg.ID, title, before, after))
+	s.addPage(pg, title, actionUpdated, names)
+	return nil
+}
+
+// relationIDs returns the resolved page IDs in source order, dropping
+// unresolved names and keeping the first occurrence of an ID that several
+// names resolved to
+func relationIDs(resolved []string) []string {
+	var ids []string
+	for _, id := range resolved {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// pagePersons returns the canonical person names held in the source fields of
+// pg, merged in field order, each once. Names differing only in case or spacing
+// count as the same person, and the first spelling is kept.
+func pagePersons(cfg config, pg notion.Page) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	for _, field := range cfg.fields {
+		prop, ok := pg.Properties[field]
+		if !ok {
+			// checkSchema catches this before the first page; this is a backstop.
+			return nil, fmt.Errorf("property %q not found on returned pages; check the exact column name in Notion", field)
+		}
+		for _, name := range extractPersons(notion.ExtractString(prop), cfg.separators, cfg.transforms) {
+			name = cfg.aliases.canonical(name)
+			key := strings.ToLower(notion.NormalizeTitle(name))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// sameIDs reports whether a and b hold the same IDs, in any order
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[string]int, len(a))
+	for _, id := range a {
+		count[id]++
+	}
+	for _, id := range b {
+		if count[id] == 0 {
+			return false
+		}
+		count[id]--
+	}
+	return true
+}
+
+// planSync prints what a dry run would write for a page
+func planSync(cfg config, ids, missing []string) {
+	for _, name := range missing {
+		infof("Would create people page for %s\n", name)
+	}
+	if cfg.mode == modeCreatePeople {
+		return
+	}
+	switch {
+	case len(ids) == 0 && len(missing) == 0:
+		infof("Would leave %s empty: no persons found\n", cfg.relationField)
+	case len(missing) > 0:
+		infof("Would set %s to %s plus %d new page(s)\n", cfg.relationField, strings.Join(ids, ", "), len(missing))
+	default:
+		infof("Would set %s to %s\n", cfg.relationField, strings.Join(ids, ", "))
+	}
+}
+
+// resolvePerson returns the ID of the people page titled name, creating it
+// when missing unless running in link-relations mode, where a missing page is an error.
+// In a dry run nothing is created and a missing page yields an empty ID.
+func (s *Syncer) resolvePerson(ctx context.Context, personName string) (string, error) {
+	cfg, people := s.cfg, s.people
+	if cfg.dryRun && cfg.mode != modeLinkRelations {
+		pageID, err := people.Lookup(ctx, personName)
+		if err != nil {
+			return "", fmt.Errorf("failed to check for existing people page for %s: %w", personName, err)
+		}
+		if pageID != "" {
+			debugf("Found existing page for %s: %s\n", personName, pageID)
+			s.personReused(personName)
+		}
+		return pageID, nil
+	}
+
+	if cfg.mode == modeLinkRelations {
+		pageID, err := people.Lookup(ctx, personName)
+		if err != nil {
+			return "", fmt.Errorf("failed to check for existing people page for %s: %w", personName, err)
+		}
+		if pageID == "" {
+			return "", fmt.Errorf("no people page for %s; run -mode create-people first", personName)
+		}
+		debugf("Found existing page for %s: %s\n", personName, pageID)
+		s.personReused(personName)
+		return pageID, nil
+	}
+
+	pageID, created, err := people.Resolve(ctx, personName)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve people page for %s: %w", personName, err)
+	}
+	if created {
+		infof("Created new page for %s: %s\n", personName, pageID)
+		s.personCreated(personName)
+	} else {
+		debugf("Found existing page for %s: %s\n", personName, pageID)
+		s.personReused(personName)
+	}
+	return pageID, nil
+}
+
+// reapplyRelation re-reads a page after a conflicting edit, merges the relation
+// it now has in field with ids, and retries the update once. It returns the
+// relation as re-read and as written.
+func reapplyRelation(ctx context.Context, client notion.PageAPI, pageID, field string, ids []string) (before, after []string, err error) {
+	current, err := client.GetPage(ctx, pageID, field)
+	if err != nil {
+		return nil, nil, fmt.Errorf("re-read after conflict: %w", err)
+	}
+
+	relation := current.Properties[field]
+	if relation.HasMore && relation.ID != "" {
+		// Merging with a truncated relation would drop the pages left out.
+		if relation, err = client.GetFullProperty(ctx, pageID, relation.ID); err != nil {
+			return nil, nil, fmt.Errorf("re-read after conflict: %w", err)
+		}
+	}
+	before = notion.ExtractStrings(relation)
+	merged := slices.Clone(before)
+	seen := make(map[string]bool, len(merged))
+	for _, id := range merged {
+		seen[id] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			merged = append(merged, id)
+		}
+	}
+
+	err = client.UpdatePage(ctx, pageID, map[string]notion.PropertyValue{
+		field: notion.RelationValue(merged...),
+	})
+	return before, merged, err
+}
+
+// extractPersons splits who on any of the separators, applies the transforms
+// in order to every name, trims it and drops empty ones. Where separators
+// overlap the longest one wins, so " and " and "," can be combined.
+func extractPersons(who string, separators []string, transforms []nameTransform) []string {
+	var persons []string
+	add := func(p string) {
+		for _, t := range transforms {
+			p = t(p)
+		}
+		if p = strings.TrimSpace(p); p != "" {
+			persons = append(persons, p)
+		}
+	}
+
+	start := 0
+	for i := 0; i < len(who); {
+		n := 0
+		for _, sep := range separators {
+			if len(sep) > n && strings.HasPrefix(who[i:], sep) {
+				n = len(sep)
+			}
+		}
+		if n == 0 {
+			i++
+			continue
+		}
+		add(who[start:i])
+		i += n
+		start = i
+	}
+	add(who[start:])
+	return persons
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"notion-tools/internal/notion"
+)
+
+func TestExtractPersonsSeparators(t *testing.T) {
+	tests := []struct {
+		name       string
+		who        string
+		separators []string
+		want       []string
+	}{
+		{"default", "Alice, Bob", []string{", "}, []string{"Alice", "Bob"}},
+		{"default keeps and", "Alice and Bob, Carol", []string{", "}, []string{"Alice and Bob", "Carol"}},
+		{"mixed in one cell", "Alice and Bob & Carol; Dave, Eve", []string{", ", " and ", " & ", ";"}, []string{"Alice", "Bob", "Carol", "Dave", "Eve"}},
+		{"longest separator wins", "Alice, and Bob", []string{",", ", and "}, []string{"Alice", "Bob"}},
+		{"empties dropped", ";; Alice ;;; Bob;", []string{";"}, []string{"Alice", "Bob"}},
+		{"separator only", " and ", []string{" and "}, nil},
+		{"empty cell", "", []string{", "}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractPersons(tt.who, tt.separators, nil)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("extractPersons(%q, %q) = %q, want %q", tt.who, tt.separators, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPagePersonsDedup(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]string
+		want   []string
+	}{
+		{"repeated", map[string]string{"Who": "Alice, Bob, Alice"}, []string{"Alice", "Bob"}},
+		{"differently cased", map[string]string{"Who": "alice, Bob, ALICE, Alice"}, []string{"alice", "Bob"}},
+		{"differently spaced", map[string]string{"Who": "Mary  Ann, Mary Ann"}, []string{"Mary  Ann"}},
+		{"across fields", map[string]string{"Who": "Carol, Bob", "Guests": "bob, Dave, carol"}, []string{"Carol", "Bob", "Dave"}},
+		{"no duplicates", map[string]string{"Who": "Bob, Alice"}, []string{"Bob", "Alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config{fields: []string{"Who", "Guests"}, separators: []string{", "}}
+			pg := notion.Page{ID: "page-1", Properties: map[string]notion.PropertyValue{}}
+			for _, field := range cfg.fields {
+				pg.Properties[field] = notion.RichTextValue(tt.fields[field])
+			}
+			got, err := pagePersons(cfg, pg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pagePersons = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncPageRelationOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		who      string
+		existing []string // people pages that exist before the sync
+		cached   []string // names resolved before the page, e.g. by an earlier page
+		want     []string // titles of the related pages, in relation order
+	}{
+		{"all new", "Carol, Alice, Bob", nil, nil, []string{"Carol", "Alice", "Bob"}},
+		{"all existing", "Carol, Alice, Bob", []string{"Alice", "Bob", "Carol"}, nil, []string{"Carol", "Alice", "Bob"}},
+		{"mixed", "Dave, Alice, Erin, Bob", []string{"Bob", "Alice"}, nil, []string{"Dave", "Alice", "Erin", "Bob"}},
+		{"cached later names", "Alice, Bob, Carol", []string{"Carol", "Bob"}, []string{"Carol", "Bob"}, []string{"Alice", "Bob", "Carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeNotion(t, tt.existing)
+			cfg := syncConfig()
+			cfg.titleField = "Name"
+			s := NewSyncer(srv.client(), cfg)
+			for _, name := range tt.cached {
+				if _, _, err := s.people.Resolve(t.Context(), name); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			pg := notion.Page{ID: "page-1", Properties: map[string]notion.PropertyValue{
+				"Name":   notion.TitleValue("Chronicle"),
+				"Who":    notion.RichTextValue(tt.who),
+				"People": {Type: "relation"},
+			}}
+			if err := s.syncPage(t.Context(), pg); err != nil {
+				t.Fatal(err)
+			}
+			if got := srv.relationTitles("page-1"); !slices.Equal(got, tt.want) {
+				t.Errorf("relation = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncRelationOrderConcurrent(t *testing.T) {
+	srv := newFakeNotion(t, []string{"Erin"})
+	srv.sources = []fakeSource{
+		{"p1", "Carol, Alice, Bob"},
+		{"p2", "Bob, Erin, Carol"},
+		{"p3", "Alice, Dave, Bob, Erin"},
+		{"p4", "Erin, Dave, Alice, Carol"},
+	}
+	cfg := syncConfig()
+	cfg.concurrency = 4
+	if _, err := NewSyncer(srv.client(), cfg).Run(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	// Pages race to create the people they share, yet each relation lists them as its cell does.
+	for _, src := range srv.sources {
+		if got, want := srv.relationTitles(src.id), extractPersons(src.who, cfg.separators, nil); !slices.Equal(got, want) {
+			t.Errorf("relation of %s = %q, want %q", src.id, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// readWatermark returns the start time of the previous successful run stored
+// in path, or the zero time when the file does not exist yet.
+func readWatermark(path string) (time.Time, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("read watermark: %w", err)
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(b)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse watermark %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// writeWatermark atomically replaces the watermark file with t
+func writeWatermark(path string, t time.Time) error {
+	if err := writeFileAtomic(path, []byte(t.UTC().Format(time.RFC3339)+"\n")); err != nil {
+		return fmt.Errorf("write watermark: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data via a temporary file in the same
+// directory, so a crash never leaves a half-written file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
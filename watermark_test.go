@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notion-tools/internal/notion"
+)
+
+func TestSyncWatermark(t *testing.T) {
+	previous := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		previous   time.Time // zero when no watermark file exists
+		overlap    time.Duration
+		fail       bool
+		dryRun     bool
+		wantFilter time.Time // zero when the query must not be filtered
+		wantMoved  bool      // whether the watermark becomes this run's start
+	}{
+		{name: "first run", wantMoved: true},
+		{name: "filters from the previous start", previous: previous, overlap: 5 * time.Minute, wantFilter: previous.Add(-5 * time.Minute), wantMoved: true},
+		{name: "no overlap", previous: previous, wantFilter: previous, wantMoved: true},
+		{name: "failed run", previous: previous, overlap: time.Hour, fail: true, wantFilter: previous.Add(-time.Hour)},
+		{name: "dry run", previous: previous, overlap: time.Hour, dryRun: true, wantFilter: previous.Add(-time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeNotion(t, nil)
+			srv.sources = []fakeSource{{"p1", "Alice"}, {"p2", "Bob"}}
+			if tt.fail {
+				srv.failUpdate = map[string]bool{"p2": true}
+			}
+			cfg := syncConfig()
+			cfg.sinceFile = filepath.Join(t.TempDir(), "watermark")
+			cfg.overlap = tt.overlap
+			cfg.dryRun = tt.dryRun
+			if !tt.previous.IsZero() {
+				if err := writeWatermark(cfg.sinceFile, tt.previous); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// The watermark has second precision, so the start is compared in whole seconds.
+			start := time.Now().Truncate(time.Second)
+			_, err := NewSyncer(srv.client(), cfg).Run(t.Context())
+			end := time.Now()
+			if tt.fail != (err != nil) {
+				t.Fatalf("err = %v, want failure %v", err, tt.fail)
+			}
+
+			var want *notion.Filter
+			if !tt.wantFilter.IsZero() {
+				want = notion.LastEditedOnOrAfter(tt.wantFilter)
+			}
+			got, _ := json.Marshal(srv.queries[0].Filter)
+			if wantJSON, _ := json.Marshal(want); string(got) != string(wantJSON) {
+				t.Errorf("query filter = %s, want %s", got, wantJSON)
+			}
+
+			stored, err := readWatermark(cfg.sinceFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.wantMoved && (stored.Before(start) || stored.After(end)):
+				t.Errorf("watermark = %s, want the run's start between %s and %s", stored, start, end)
+			case !tt.wantMoved && !stored.Equal(tt.previous):
+				t.Errorf("watermark = %s, want it left at %s", stored, tt.previous)
+			}
+		})
+	}
+}